@@ -0,0 +1,45 @@
+package matchqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock1, clock2 := NewFakeClock(start), NewFakeClock(start)
+
+	q1 := New(DefaultConfig(), WithClock(clock1)).(*queue)
+	q2 := New(DefaultConfig(), WithClock(clock2)).(*queue)
+
+	var waitTimes [2]time.Duration
+	q1.Subscribe(func(n Notification) { waitTimes[0], _ = n.Data["wait_time"].(time.Duration) })
+	q2.Subscribe(func(n Notification) { waitTimes[1], _ = n.Data["wait_time"].(time.Duration) })
+
+	q1.AddPlayer([]*Player{{ID: 1, Score: 25.0}})
+	q2.AddPlayer([]*Player{{ID: 1, Score: 25.0}})
+
+	// each queue has its own time
+	clock1.Advance(10 * time.Second)
+	clock2.Advance(30 * time.Second)
+
+	q1.RemovePlayer(1, true)
+	q2.RemovePlayer(1, true)
+
+	assert.Equal(t, [2]time.Duration{10 * time.Second, 30 * time.Second}, waitTimes)
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	assert.Equal(t, start, c.Now())
+
+	c.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute), c.Now())
+
+	c.Set(start)
+	assert.Equal(t, start, c.Now())
+}
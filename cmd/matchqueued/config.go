@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/scalcor/matchqueue"
+)
+
+// serverConfig is the configuration of the server.
+//
+// Example in YAML:
+//
+//	listen: ":8080"
+//	interval: 1s
+//	queues:
+//	  deathmatch:
+//	    min_num_to_create_group: 8
+//	    max_num_to_create_group: 12
+//	  duel:
+//	    min_num_to_create_group: 2
+//	    max_num_to_create_group: 2
+//
+// Each queue's configuration is merged over matchqueue.DefaultConfig.
+type serverConfig struct {
+	Listen   string
+	Interval time.Duration
+	Queues   map[string]*matchqueue.Config
+}
+
+const (
+	defaultListen   = ":8080"
+	defaultInterval = time.Second
+)
+
+func loadServerConfig(path string) (*serverConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Listen   string         `json:"listen" yaml:"listen"`
+		Interval string         `json:"interval" yaml:"interval"`
+		Queues   map[string]any `json:"queues" yaml:"queues"`
+	}
+	if err := matchqueue.UnmarshalConfig(data, filepath.Ext(path), &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	conf := &serverConfig{Listen: raw.Listen, Interval: defaultInterval, Queues: map[string]*matchqueue.Config{}}
+	if conf.Listen == "" {
+		conf.Listen = defaultListen
+	}
+	if raw.Interval != "" {
+		if conf.Interval, err = time.ParseDuration(raw.Interval); err != nil {
+			return nil, fmt.Errorf("%s: interval: %w", path, err)
+		}
+		if conf.Interval <= 0 {
+			return nil, fmt.Errorf("%s: interval must be positive", path)
+		}
+	}
+
+	if len(raw.Queues) == 0 {
+		return nil, fmt.Errorf("%s: no queue is defined", path)
+	}
+	for name, v := range raw.Queues {
+		// decode the queue's section again over the default configuration
+		section, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: queue %q: %w", path, name, err)
+		}
+
+		qconf := matchqueue.DefaultConfig()
+		if v != nil {
+			if err := json.Unmarshal(section, qconf); err != nil {
+				return nil, fmt.Errorf("%s: queue %q: %w", path, name, err)
+			}
+		}
+		if err := qconf.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: queue %q: %w", path, name, err)
+		}
+		conf.Queues[name] = qconf
+	}
+
+	return conf, nil
+}
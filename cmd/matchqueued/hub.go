@@ -0,0 +1,59 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/scalcor/matchqueue"
+)
+
+// groupHubSize is the number of recent groups kept for long-polling and resuming streams.
+const groupHubSize = 1024
+
+// groupHub keeps recent groups of a queue and wakes up waiting clients when a group is created.
+type groupHub struct {
+	mu     sync.Mutex
+	groups []*matchqueue.Group // recent groups sorted by ID
+	notify chan struct{}       // closed and replaced when a group is published
+}
+
+func newGroupHub() *groupHub {
+	return &groupHub{notify: make(chan struct{})}
+}
+
+func (h *groupHub) publish(g *matchqueue.Group) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.groups = append(h.groups, g)
+	if len(h.groups) > groupHubSize {
+		h.groups = h.groups[len(h.groups)-groupHubSize:]
+	}
+
+	close(h.notify)
+	h.notify = make(chan struct{})
+}
+
+// after returns groups whose ID is greater than id,
+// and the channel which is closed when the next group is published.
+func (h *groupHub) after(id matchqueue.GroupID) ([]*matchqueue.Group, <-chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, g := range h.groups {
+		if g.ID > id {
+			return append([]*matchqueue.Group(nil), h.groups[i:]...), h.notify
+		}
+	}
+	return nil, h.notify
+}
+
+// last returns ID of the latest group.
+func (h *groupHub) last() matchqueue.GroupID {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.groups) == 0 {
+		return 0
+	}
+	return h.groups[len(h.groups)-1].ID
+}
@@ -0,0 +1,92 @@
+// Command matchqueued serves matching queues over HTTP/JSON.
+//
+// Usage:
+//
+//	matchqueued -config queues.yaml
+//
+// Queues are defined by the configuration file; see serverConfig. Endpoints are:
+//
+//	GET    /queues                         names of queues
+//	POST   /parties                        enroll a party in several queues: {"queues": ["a", "b"], "players": [...]}
+//	POST   /queues/{name}/parties          enqueue a party: {"players": [{"id": 1, "score": 25.0}]}
+//	DELETE /queues/{name}/parties/{leader} cancel the party of the leader in the queue
+//	GET    /queues/{name}/state            state of the queue
+//	GET    /queues/{name}/groups           created groups; long-poll with ?after={group id}&timeout={duration},
+//	                                       or server-sent events with "Accept: text/event-stream"
+//
+// A player can be in only one party at a time; enqueueing a queued player fails with 409 Conflict.
+// A party enrolled in several queues is withdrawn from the others when one of them matches it.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	configPath := flag.String("config", "", "path of the server configuration (JSON or YAML)")
+	flag.Parse()
+
+	if err := run(*configPath); err != nil {
+		fmt.Fprintln(os.Stderr, "matchqueued:", err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string) error {
+	if configPath == "" {
+		return errors.New("config is required")
+	}
+
+	conf, err := loadServerConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	s, err := newServer(conf)
+	if err != nil {
+		return err
+	}
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	httpServer := &http.Server{Addr: conf.Listen, Handler: s}
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("listening on %s with %d queues", conf.Listen, len(conf.Queues))
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCancel()
+
+	// stop matching first so that streams of groups are closed
+	stopErr := s.stop(shutdownCtx)
+	if shutdownErr := httpServer.Shutdown(shutdownCtx); shutdownErr != nil {
+		stopErr = errors.Join(stopErr, shutdownErr)
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return stopErr
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/scalcor/matchqueue"
+)
+
+const (
+	defaultPollTimeout = 30 * time.Second
+	maxPollTimeout     = 5 * time.Minute
+)
+
+// server serves matching queues over HTTP.
+type server struct {
+	manager *matchqueue.Manager
+	hubs    map[string]*groupHub // hub of each queue
+	done    chan struct{}        // closed when all groups of the manager are published
+	mux     *http.ServeMux
+}
+
+func newServer(conf *serverConfig) (*server, error) {
+	s := &server{
+		manager: matchqueue.NewManager(conf.Interval),
+		hubs:    map[string]*groupHub{},
+		done:    make(chan struct{}),
+		mux:     http.NewServeMux(),
+	}
+
+	for name, qconf := range conf.Queues {
+		if err := s.manager.AddQueue(name, qconf); err != nil {
+			return nil, err
+		}
+		s.hubs[name] = newGroupHub()
+	}
+
+	s.mux.HandleFunc("GET /queues", s.handleListQueues)
+	s.mux.HandleFunc("POST /parties", s.handleEnroll)
+	s.mux.HandleFunc("POST /queues/{name}/parties", s.handleEnqueue)
+	s.mux.HandleFunc("DELETE /queues/{name}/parties/{leader}", s.handleCancel)
+	s.mux.HandleFunc("GET /queues/{name}/state", s.handleState)
+	s.mux.HandleFunc("GET /queues/{name}/groups", s.handleGroups)
+
+	return s, nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// start starts the matching loop of the queues.
+func (s *server) start() error {
+	if err := s.manager.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(s.done)
+		for g := range s.manager.Groups() {
+			s.hubs[g.Queue].publish(g.Group)
+		}
+	}()
+	return nil
+}
+
+// stop stops the matching loop of the queues.
+func (s *server) stop(ctx context.Context) error {
+	if err := s.manager.Stop(ctx); err != nil && !errors.Is(err, matchqueue.ErrRunnerNotRunning) {
+		return err
+	}
+	return nil
+}
+
+// queue returns the name of the queue of the request.
+func (s *server) queue(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := r.PathValue("name")
+	if _, ok := s.hubs[name]; !ok {
+		writeError(w, http.StatusNotFound, "unknown queue")
+		return "", false
+	}
+	return name, true
+}
+
+func (s *server) handleListQueues(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"queues": s.manager.Queues()})
+}
+
+// handleEnqueue adds a party to the queue.
+//
+// Request body: {"players": [{"id": 1, "score": 25.0}, ...]}
+// The first player is the leader of the party.
+func (s *server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.queue(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Players []*matchqueue.Player `json:"players"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.enroll(w, []string{name}, req.Players)
+}
+
+// handleEnroll adds a party to several queues at once.
+// When a queue matches the party, it is withdrawn from the other queues.
+//
+// Request body: {"queues": ["deathmatch", "capture"], "players": [{"id": 1, "score": 25.0}, ...]}
+func (s *server) handleEnroll(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Queues  []string             `json:"queues"`
+		Players []*matchqueue.Player `json:"players"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Queues) == 0 {
+		writeError(w, http.StatusBadRequest, "no queue")
+		return
+	}
+	for _, name := range req.Queues {
+		if _, ok := s.hubs[name]; !ok {
+			writeError(w, http.StatusNotFound, "unknown queue")
+			return
+		}
+	}
+
+	s.enroll(w, req.Queues, req.Players)
+}
+
+func (s *server) enroll(w http.ResponseWriter, names []string, players []*matchqueue.Player) {
+	if len(players) == 0 {
+		writeError(w, http.StatusBadRequest, "no player")
+		return
+	}
+	for _, pl := range players {
+		if pl == nil || !pl.ID.IsValid() {
+			writeError(w, http.StatusBadRequest, "invalid player id")
+			return
+		}
+	}
+
+	if err := s.manager.Enroll(names, players); err != nil {
+		switch {
+		case errors.Is(err, matchqueue.ErrPlayerQueued):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, matchqueue.ErrConstraintViolated):
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, matchqueue.ErrUnknownQueue):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, matchqueue.ErrNoQueue):
+			writeError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]any{"leader": players[0].ID})
+}
+
+// handleCancel removes the party of the leader from the queue.
+// The party stays in the other queues where it is enrolled.
+func (s *server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.queue(w, r)
+	if !ok {
+		return
+	}
+
+	leader, err := strconv.ParseUint(r.PathValue("leader"), 10, 64)
+	if err != nil || !matchqueue.PlayerID(leader).IsValid() {
+		writeError(w, http.StatusBadRequest, "invalid leader id")
+		return
+	}
+
+	if err := s.manager.Withdraw(name, matchqueue.PlayerID(leader), true); err != nil {
+		switch {
+		case errors.Is(err, matchqueue.ErrPlayerNotQueued), errors.Is(err, matchqueue.ErrUnknownQueue):
+			writeError(w, http.StatusNotFound, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) handleState(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.queue(w, r)
+	if !ok {
+		return
+	}
+
+	state, err := s.manager.QueueState(name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// handleGroups returns created groups.
+//
+// If the request accepts text/event-stream, groups are streamed as server-sent events
+// whose id is the group ID. Otherwise it is a long-poll:
+// groups whose ID is greater than the "after" parameter are returned,
+// waiting up to the "timeout" parameter (default 30s) until any group is created.
+func (s *server) handleGroups(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.queue(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Header.Get("Accept") == "text/event-stream" {
+		s.streamGroups(w, r, s.hubs[name])
+		return
+	}
+
+	var after uint64
+	if v := r.URL.Query().Get("after"); v != "" {
+		var err error
+		if after, err = strconv.ParseUint(v, 10, 64); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid after")
+			return
+		}
+	}
+
+	timeout := defaultPollTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		var err error
+		if timeout, err = time.ParseDuration(v); err != nil || timeout < 0 {
+			writeError(w, http.StatusBadRequest, "invalid timeout")
+			return
+		}
+		timeout = min(timeout, maxPollTimeout)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		groups, next := s.hubs[name].after(matchqueue.GroupID(after))
+		if len(groups) > 0 {
+			writeJSON(w, http.StatusOK, map[string]any{"groups": groups})
+			return
+		}
+
+		select {
+		case <-next:
+		case <-timer.C:
+			writeJSON(w, http.StatusOK, map[string]any{"groups": []*matchqueue.Group{}})
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *server) streamGroups(w http.ResponseWriter, r *http.Request, hub *groupHub) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	// resume after the last event the client received, or start from now
+	last := hub.last()
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		if id, err := strconv.ParseUint(v, 10, 64); err == nil {
+			last = matchqueue.GroupID(id)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		groups, next := hub.after(last)
+		for _, g := range groups {
+			data, err := json.Marshal(g)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: group\ndata: %s\n\n", g.ID, data); err != nil {
+				return
+			}
+			last = g.ID
+		}
+		flusher.Flush()
+
+		select {
+		case <-next:
+		case <-s.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{"error": msg})
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scalcor/matchqueue"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "queues.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+interval: 10ms
+queues:
+  duel:
+    min_num_to_create_group: 2
+    max_num_to_create_group: 2
+    num_round_to_create_group: 1
+  team: {}
+  ranked:
+    constraints:
+      - {type: equal, attribute: platform}
+`), 0o644))
+
+	conf, err := loadServerConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Millisecond, conf.Interval)
+	assert.Equal(t, 2, conf.Queues["duel"].MaxNumToCreateGroup)
+	assert.Equal(t, matchqueue.DefaultConfig(), conf.Queues["team"])
+
+	s, err := newServer(conf)
+	require.NoError(t, err)
+	require.NoError(t, s.start())
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		assert.NoError(t, s.stop(context.Background()))
+	})
+	return ts
+}
+
+func enqueue(t *testing.T, ts *httptest.Server, queue string, ids ...int) *http.Response {
+	t.Helper()
+
+	var players []string
+	for _, id := range ids {
+		players = append(players, fmt.Sprintf(`{"id": %d, "score": 25.0}`, id))
+	}
+	resp, err := http.Post(ts.URL+"/queues/"+queue+"/parties", "application/json",
+		strings.NewReader(`{"players": [`+strings.Join(players, ",")+`]}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp
+}
+
+func lastGroupID(t *testing.T, ts *httptest.Server, queue string) matchqueue.GroupID {
+	t.Helper()
+
+	resp, err := http.Get(ts.URL + "/queues/" + queue + "/groups?timeout=0s")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body struct{ Groups []*matchqueue.Group }
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	if len(body.Groups) == 0 {
+		return 0
+	}
+	return body.Groups[len(body.Groups)-1].ID
+}
+
+func TestServer(t *testing.T) {
+	ts := newTestServer(t)
+
+	t.Run("enqueue and poll", func(t *testing.T) {
+		assert.Equal(t, http.StatusAccepted, enqueue(t, ts, "duel", 1).StatusCode)
+		assert.Equal(t, http.StatusAccepted, enqueue(t, ts, "duel", 2).StatusCode)
+
+		resp, err := http.Get(ts.URL + "/queues/duel/groups?after=0&timeout=5s")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body struct{ Groups []*matchqueue.Group }
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.Len(t, body.Groups, 1)
+		assert.EqualValues(t, 1, body.Groups[0].ID)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", ts.URL+"/queues/duel/groups", nil)
+		req.Header.Set("Accept", "text/event-stream")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		enqueue(t, ts, "duel", 3)
+		enqueue(t, ts, "duel", 4)
+
+		reader := bufio.NewReader(resp.Body)
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "id: 2\n", line)
+		line, _ = reader.ReadString('\n')
+		assert.Equal(t, "event: group\n", line)
+		line, _ = reader.ReadString('\n')
+		assert.True(t, strings.HasPrefix(line, "data: {"))
+	})
+
+	t.Run("poll timeout", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/queues/team/groups?timeout=10ms")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body struct{ Groups []*matchqueue.Group }
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Empty(t, body.Groups)
+	})
+
+	t.Run("cancel and state", func(t *testing.T) {
+		enqueue(t, ts, "team", 10, 11)
+
+		req, _ := http.NewRequest("DELETE", ts.URL+"/queues/team/parties/10", nil)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, err = http.Get(ts.URL + "/queues/team/state")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var state matchqueue.State
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&state))
+		assert.Equal(t, 2, state.PlayerCanceled)
+
+		// the party is not in the queue any more, and 20 is queued only in the other queue
+		enqueue(t, ts, "duel", 20)
+		for _, path := range []string{"/queues/team/parties/10", "/queues/team/parties/20"} {
+			req, _ := http.NewRequest("DELETE", ts.URL+path, nil)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+		}
+		assert.Equal(t, http.StatusConflict, enqueue(t, ts, "team", 20).StatusCode)
+
+		req, _ = http.NewRequest("DELETE", ts.URL+"/queues/duel/parties/20", nil)
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("enroll", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/parties", "application/json",
+			strings.NewReader(`{"queues": ["team", "duel"], "players": [{"id": 30, "score": 25.0}]}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		last := lastGroupID(t, ts, "duel")
+		enqueue(t, ts, "duel", 31)
+
+		resp, err = http.Get(fmt.Sprintf("%s/queues/duel/groups?after=%d&timeout=5s", ts.URL, last))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body struct{ Groups []*matchqueue.Group }
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.Len(t, body.Groups, 1)
+
+		// the party is withdrawn from the team queue, so it can be queued again
+		assert.Equal(t, http.StatusAccepted, enqueue(t, ts, "team", 30).StatusCode)
+
+		resp, err = http.Post(ts.URL+"/parties", "application/json",
+			strings.NewReader(`{"queues": ["team", "unknown"], "players": [{"id": 32}]}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, enqueue(t, ts, "unknown", 1).StatusCode)
+		assert.Equal(t, http.StatusBadRequest, enqueue(t, ts, "duel").StatusCode)
+		assert.Equal(t, http.StatusBadRequest, enqueue(t, ts, "duel", 0).StatusCode)
+
+		// a player can be queued in only one queue
+		assert.Equal(t, http.StatusAccepted, enqueue(t, ts, "team", 20).StatusCode)
+		assert.Equal(t, http.StatusConflict, enqueue(t, ts, "duel", 21, 20).StatusCode)
+
+		// players of the party violate constraints of the queue
+		resp, err := http.Post(ts.URL+"/queues/ranked/parties", "application/json", strings.NewReader(
+			`{"players": [{"id": 40, "attributes": {"platform": "pc"}}, {"id": 41, "attributes": {"platform": "console"}}]}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+}
@@ -0,0 +1,113 @@
+// Command matchsim replays an arrival stream of parties through a matching queue
+// with a virtual clock and reports the quality of matching.
+//
+// Usage:
+//
+//	matchsim [flags]
+//
+// Without -input, a synthetic stream is generated from the flags.
+// A recorded stream is a CSV of "arrival_sec,scores,cancel_sec" records,
+// where scores of the party's players are separated by ';' and cancel_sec is empty if the party never cancels.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scalcor/matchqueue"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "matchsim:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	var (
+		fs = flag.NewFlagSet("matchsim", flag.ContinueOnError)
+
+		configPath = fs.String("config", "", "path of the queue configuration (JSON or YAML); default configuration if empty")
+		inputPath  = fs.String("input", "", "path of the recorded arrival stream (CSV); synthetic stream if empty")
+		csvPath    = fs.String("csv", "", "path to write the queue state of each round (CSV)")
+		tick       = fs.Duration("tick", time.Second, "interval of matching rounds")
+		duration   = fs.Duration("duration", time.Hour, "maximum simulated time")
+
+		synth      = syntheticOptions{}
+		partySizes = fs.String("party-sizes", "6,2,1,1", "comma separated weights of party sizes, starting from 1 player")
+	)
+	fs.IntVar(&synth.Parties, "parties", 1000, "number of synthetic parties")
+	fs.Float64Var(&synth.Rate, "rate", 2.0, "arrival rate of synthetic parties per second")
+	fs.Float64Var(&synth.ScoreMean, "score-mean", 25.0, "mean of synthetic player scores")
+	fs.Float64Var(&synth.ScoreStdDev, "score-stddev", 8.0, "standard deviation of synthetic player scores")
+	fs.Float64Var(&synth.CancelRatio, "cancel-ratio", 0.05, "ratio of synthetic parties which cancel matching")
+	fs.Float64Var(&synth.CancelAfter, "cancel-after", 60.0, "mean wait time (second) before synthetic parties cancel")
+	fs.Int64Var(&synth.Seed, "seed", 1, "random seed of the synthetic stream")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *tick <= 0 {
+		return fmt.Errorf("tick must be positive")
+	}
+
+	conf := matchqueue.DefaultConfig()
+	if *configPath != "" {
+		var err error
+		if conf, err = matchqueue.LoadConfig(*configPath); err != nil {
+			return err
+		}
+	}
+
+	var arrivals []arrival
+	if *inputPath != "" {
+		f, err := os.Open(*inputPath)
+		if err != nil {
+			return err
+		}
+		arrivals, err = readArrivals(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", *inputPath, err)
+		}
+	} else {
+		for _, s := range strings.Split(*partySizes, ",") {
+			w, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			if err != nil {
+				return fmt.Errorf("party-sizes: %w", err)
+			}
+			synth.PartySizes = append(synth.PartySizes, w)
+		}
+		if synth.Rate <= 0 {
+			return fmt.Errorf("rate must be positive")
+		}
+		arrivals = generateArrivals(synth)
+	}
+
+	sim := &simulator{conf: conf, tick: *tick, duration: *duration}
+	res, err := sim.run(arrivals)
+	if err != nil {
+		return err
+	}
+
+	writeText(stdout, res)
+
+	if *csvPath != "" {
+		f, err := os.Create(*csvPath)
+		if err != nil {
+			return err
+		}
+		if err := writeCSV(f, res); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return nil
+}
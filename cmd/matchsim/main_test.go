@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scalcor/matchqueue"
+)
+
+func Test_readArrivals(t *testing.T) {
+	input := "arrival_sec,scores,cancel_sec\n" +
+		"2.5,20;30,\n" +
+		"1,25,4\n"
+
+	got, err := readArrivals(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, []arrival{
+		{At: time.Second, Scores: []float64{25.0}, CancelAt: 4 * time.Second},
+		{At: 2500 * time.Millisecond, Scores: []float64{20.0, 30.0}},
+	}, got)
+
+	_, err = readArrivals(strings.NewReader("3,25,1\n"))
+	assert.Error(t, err)
+}
+
+func Test_percentile(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	assert.Equal(t, 5, percentile(values, 50))
+	assert.Equal(t, 9, percentile(values, 90))
+	assert.Equal(t, 10, percentile(values, 99))
+	assert.Equal(t, 1, percentile(values, 0))
+	assert.Equal(t, 0, percentile([]int{}, 50))
+}
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.csv")
+	output := filepath.Join(dir, "output.csv")
+
+	// 12 players arrive in a second, and one of them cancels
+	var records []string
+	for i := range 12 {
+		records = append(records, "0."+string(rune('0'+i%10))+",25,")
+	}
+	records[11] = "0.5,25,0.9"
+	require.NoError(t, os.WriteFile(input, []byte(strings.Join(records, "\n")), 0o644))
+
+	var stdout bytes.Buffer
+	require.NoError(t, run([]string{"-input", input, "-csv", output, "-tick", "1s", "-duration", "10s"}, &stdout))
+
+	assert.Contains(t, stdout.String(), "parties:            arrived 12, matched 11, canceled 1, remaining 0")
+	assert.Contains(t, stdout.String(), "groups:             1")
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "time_sec,queued_players,queued_parties,groups_created,match_window\n1,11,11,0,"))
+}
+
+func Test_simulator_run(t *testing.T) {
+	conf := matchqueue.DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.NumRoundToCreateGroup = 1
+	sim := &simulator{conf: conf, tick: time.Second, duration: 5 * time.Second}
+
+	// parties cancel before later arrivals of the same tick, and one cancels after it is matched
+	arrivals := []arrival{
+		{At: 100 * time.Millisecond, Scores: []float64{25}, CancelAt: 300 * time.Millisecond},
+		{At: 200 * time.Millisecond, Scores: []float64{25}, CancelAt: 400 * time.Millisecond},
+		{At: 800 * time.Millisecond, Scores: []float64{25}},
+		{At: 900 * time.Millisecond, Scores: []float64{25}, CancelAt: 1500 * time.Millisecond},
+		{At: 1200 * time.Millisecond, Scores: []float64{25}},
+	}
+
+	first, err := sim.run(arrivals)
+	require.NoError(t, err)
+	assert.Equal(t, 5, first.Arrived)
+	assert.Equal(t, 2, first.Matched)
+	assert.Equal(t, 2, first.Canceled)
+	assert.Equal(t, []time.Duration{200 * time.Millisecond, 100 * time.Millisecond}, first.WaitTimes)
+
+	// simulations are reproducible
+	for range 10 {
+		res, err := sim.run(arrivals)
+		require.NoError(t, err)
+		assert.Equal(t, first, res)
+	}
+}
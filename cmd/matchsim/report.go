@@ -0,0 +1,85 @@
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// percentile returns the p-th percentile of the values by the nearest-rank method.
+func percentile[T cmp.Ordered](sorted []T, p float64) T {
+	var zero T
+	if len(sorted) == 0 {
+		return zero
+	}
+	rank := int(math.Ceil(p / 100.0 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+func average[T ~int | ~int64 | ~float64](values []T) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += float64(v)
+	}
+	return sum / float64(len(values))
+}
+
+// writeText writes the summary of the result.
+func writeText(w io.Writer, res *result) {
+	waits := slices.Clone(res.WaitTimes)
+	slices.Sort(waits)
+	spreads := slices.Clone(res.ScoreSpread)
+	slices.Sort(spreads)
+	imbalances := slices.Clone(res.Imbalance)
+	slices.Sort(imbalances)
+
+	depths := make([]int, len(res.Samples))
+	for i, s := range res.Samples {
+		depths[i] = s.QueuedPlayers
+	}
+
+	fmt.Fprintf(w, "rounds:             %d (%v simulated)\n", res.Rounds, res.Elapsed)
+	fmt.Fprintf(w, "parties:            arrived %d, matched %d, canceled %d, remaining %d\n",
+		res.Arrived, res.Matched, res.Canceled, res.Arrived-res.Matched-res.Canceled)
+	fmt.Fprintf(w, "groups:             %d\n", res.Groups)
+	fmt.Fprintf(w, "wait time:          avg %v, p50 %v, p90 %v, p99 %v, max %v\n",
+		time.Duration(average(waits)).Round(time.Millisecond),
+		percentile(waits, 50).Round(time.Millisecond), percentile(waits, 90).Round(time.Millisecond),
+		percentile(waits, 99).Round(time.Millisecond), percentile(waits, 100).Round(time.Millisecond))
+	fmt.Fprintf(w, "group score spread: avg %.2f, p50 %.2f, p90 %.2f, max %.2f\n",
+		average(spreads), percentile(spreads, 50), percentile(spreads, 90), percentile(spreads, 100))
+	fmt.Fprintf(w, "team imbalance:     avg %.2f, p50 %.2f, p90 %.2f, max %.2f\n",
+		average(imbalances), percentile(imbalances, 50), percentile(imbalances, 90), percentile(imbalances, 100))
+	fmt.Fprintf(w, "queue depth:        avg %.1f, max %d players\n", average(depths), slices.Max(append(depths, 0)))
+}
+
+// writeCSV writes the queue's state after each round.
+func writeCSV(w io.Writer, res *result) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time_sec", "queued_players", "queued_parties", "groups_created", "match_window"}); err != nil {
+		return err
+	}
+
+	for _, s := range res.Samples {
+		if err := cw.Write([]string{
+			strconv.FormatFloat(s.At.Seconds(), 'f', -1, 64),
+			strconv.Itoa(s.QueuedPlayers),
+			strconv.Itoa(s.QueuedParties),
+			strconv.Itoa(s.GroupsCreated),
+			strconv.FormatFloat(s.MatchWindow, 'f', 3, 64),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
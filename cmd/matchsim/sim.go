@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/scalcor/matchqueue"
+)
+
+// sample is a snapshot of the queue after a matching round.
+type sample struct {
+	At            time.Duration
+	QueuedPlayers int
+	QueuedParties int
+	GroupsCreated int
+	MatchWindow   float64
+}
+
+// result is the outcome of a simulation.
+type result struct {
+	Rounds   int
+	Elapsed  time.Duration
+	Arrived  int
+	Matched  int // number of matched parties
+	Canceled int
+	Groups   int
+
+	WaitTimes   []time.Duration // wait time of each matched player
+	ScoreSpread []float64       // difference between the highest and the lowest score of each group
+	Imbalance   []float64       // difference between the highest and the lowest team score of each group
+	Samples     []sample
+}
+
+// simulator replays an arrival stream through a queue with a virtual clock.
+type simulator struct {
+	conf     *matchqueue.Config
+	tick     time.Duration
+	duration time.Duration // simulation stops after the duration even if parties remain
+}
+
+func (s *simulator) run(arrivals []arrival) (*result, error) {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := matchqueue.NewFakeClock(start)
+
+	q, err := matchqueue.NewQueue(s.conf, matchqueue.WithClock(clock))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{}
+
+	// arrivals and cancels are replayed in the order of time, arrivals first at the same time
+	type event struct {
+		at     time.Duration
+		idx    int // index of the arrival
+		cancel bool
+	}
+	var events []event
+	for i, a := range arrivals {
+		events = append(events, event{at: a.At, idx: i})
+		if a.CancelAt > 0 {
+			events = append(events, event{at: max(a.CancelAt, a.At), idx: i, cancel: true})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at < events[j].at })
+
+	var (
+		leaders       = make([]matchqueue.PlayerID, len(arrivals)) // leader of each arrived party
+		queuedParties = map[matchqueue.PlayerID]int{}              // number of players keyed by leader
+		queuedPlayers int
+		nextID        matchqueue.PlayerID
+		next          int // index of the next event
+	)
+
+	// advance moves the clock forward to the time; it never goes backwards
+	now := time.Duration(0)
+	advance := func(at time.Duration) {
+		if at > now {
+			now = at
+			clock.Set(start.Add(now))
+		}
+	}
+
+	for elapsed := s.tick; elapsed <= s.duration; elapsed += s.tick {
+		// apply events until now
+		for ; next < len(events) && events[next].at <= elapsed; next++ {
+			e := events[next]
+			advance(e.at)
+
+			if e.cancel {
+				leader := leaders[e.idx]
+				cnt, ok := queuedParties[leader]
+				if !ok {
+					// already matched
+					continue
+				}
+				q.RemovePlayer(leader, true)
+
+				delete(queuedParties, leader)
+				queuedPlayers -= cnt
+				res.Canceled++
+				continue
+			}
+
+			var players []*matchqueue.Player
+			for _, score := range arrivals[e.idx].Scores {
+				nextID++
+				players = append(players, &matchqueue.Player{ID: nextID, Score: score})
+			}
+			if err := q.AddPlayer(players); err != nil {
+				return nil, err
+			}
+
+			leaders[e.idx] = players[0].ID
+			queuedParties[players[0].ID] = len(players)
+			queuedPlayers += len(players)
+			res.Arrived++
+		}
+
+		advance(elapsed)
+		groups, err := q.ProcMatching()
+		if err != nil && !errors.Is(err, matchqueue.ErrNotEnoughPlayer) {
+			return nil, err
+		}
+		res.Rounds++
+		res.Elapsed = elapsed
+
+		for _, g := range groups {
+			for _, gp := range g.Parties {
+				delete(queuedParties, gp.Leader)
+				queuedPlayers -= len(gp.Players)
+				res.Matched++
+
+				for range gp.Players {
+					res.WaitTimes = append(res.WaitTimes, gp.WaitTime)
+				}
+			}
+			res.ScoreSpread = append(res.ScoreSpread, g.ScoreSpread)
+
+			lowest, highest := g.TeamScores[0], g.TeamScores[0]
+			for _, score := range g.TeamScores {
+				lowest, highest = min(lowest, score), max(highest, score)
+			}
+			res.Imbalance = append(res.Imbalance, highest-lowest)
+		}
+		res.Groups += len(groups)
+
+		res.Samples = append(res.Samples, sample{
+			At:            elapsed,
+			QueuedPlayers: queuedPlayers,
+			QueuedParties: len(queuedParties),
+			GroupsCreated: len(groups),
+			MatchWindow:   q.State().MatchWindow,
+		})
+
+		if res.Arrived == len(arrivals) && len(queuedParties) == 0 {
+			// all parties are matched or canceled
+			break
+		}
+	}
+
+	return res, nil
+}
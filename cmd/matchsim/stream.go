@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// arrival is a party joining the queue.
+type arrival struct {
+	At       time.Duration // arrival time since the start of simulation
+	Scores   []float64     // score of each player
+	CancelAt time.Duration // cancel time since the start of simulation; 0 means never
+}
+
+// syntheticOptions describes a randomly generated arrival stream.
+type syntheticOptions struct {
+	Parties     int
+	Rate        float64   // parties per second
+	ScoreMean   float64   // mean of player scores
+	ScoreStdDev float64   // standard deviation of player scores
+	PartySizes  []float64 // weight of each party size; index 0 is for the party of 1 player
+	CancelRatio float64   // ratio of parties which cancel matching
+	CancelAfter float64   // mean wait time (second) before cancel
+	Seed        int64
+}
+
+// generateArrivals creates a random arrival stream.
+// Arrivals follow a poisson process, and scores follow a normal distribution.
+func generateArrivals(opts syntheticOptions) []arrival {
+	rnd := rand.New(rand.NewSource(opts.Seed))
+
+	totalWeight := 0.0
+	for _, w := range opts.PartySizes {
+		totalWeight += w
+	}
+
+	partySize := func() int {
+		r := rnd.Float64() * totalWeight
+		for i, w := range opts.PartySizes {
+			if r < w {
+				return i + 1
+			}
+			r -= w
+		}
+		return 1
+	}
+
+	arrivals := make([]arrival, 0, opts.Parties)
+	at := 0.0
+	for range opts.Parties {
+		at += rnd.ExpFloat64() / opts.Rate
+
+		a := arrival{At: seconds(at)}
+		for range partySize() {
+			a.Scores = append(a.Scores, max(rnd.NormFloat64()*opts.ScoreStdDev+opts.ScoreMean, 0.0))
+		}
+		if rnd.Float64() < opts.CancelRatio {
+			a.CancelAt = a.At + seconds(rnd.ExpFloat64()*opts.CancelAfter)
+		}
+
+		arrivals = append(arrivals, a)
+	}
+	return arrivals
+}
+
+// readArrivals reads a recorded arrival stream in CSV.
+// Each record is "arrival_sec,scores,cancel_sec" where scores are separated by ';'
+// and cancel_sec is empty if the party never cancels. The first record may be a header.
+func readArrivals(r io.Reader) ([]arrival, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	var arrivals []arrival
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if line == 1 && record[0] == "arrival_sec" {
+			// header
+			continue
+		}
+
+		a, err := parseArrival(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		arrivals = append(arrivals, a)
+	}
+
+	sort.SliceStable(arrivals, func(i, j int) bool { return arrivals[i].At < arrivals[j].At })
+	return arrivals, nil
+}
+
+func parseArrival(record []string) (a arrival, err error) {
+	at, err := strconv.ParseFloat(record[0], 64)
+	if err != nil {
+		return a, err
+	}
+	a.At = seconds(at)
+
+	for _, s := range strings.Split(record[1], ";") {
+		score, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return a, err
+		}
+		a.Scores = append(a.Scores, score)
+	}
+
+	if record[2] != "" {
+		cancelAt, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return a, err
+		}
+		if a.CancelAt = seconds(cancelAt); a.CancelAt < a.At {
+			return a, fmt.Errorf("cancel_sec %v is before arrival_sec %v", cancelAt, at)
+		}
+	}
+
+	return a, nil
+}
+
+func seconds(sec float64) time.Duration {
+	return time.Duration(sec * float64(time.Second))
+}
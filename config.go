@@ -1,27 +1,98 @@
 package matchqueue
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
 type Config struct {
 	// match window
-	InitMatchWindow      float64 `json:"init_match_window"`
-	MinMatchWindow       float64 `json:"min_match_window"`
-	MaxMatchWindow       float64 `json:"max_match_window"`
-	WindowAdjustStep     float64 `json:"window_adjust_step"`
-	MinRateToKeepWindow  float64 `json:"min_rate_to_keep_window"`
-	MaxRateToKeepWindow  float64 `json:"max_rate_to_keep_window"`
-	WindowAdjustPerRetry float64 `json:"window_adjust_per_retry"`
+	InitMatchWindow      float64 `json:"init_match_window" yaml:"init_match_window"`
+	MinMatchWindow       float64 `json:"min_match_window" yaml:"min_match_window"`
+	MaxMatchWindow       float64 `json:"max_match_window" yaml:"max_match_window"`
+	WindowAdjustStep     float64 `json:"window_adjust_step" yaml:"window_adjust_step"`
+	MinRateToKeepWindow  float64 `json:"min_rate_to_keep_window" yaml:"min_rate_to_keep_window"`
+	MaxRateToKeepWindow  float64 `json:"max_rate_to_keep_window" yaml:"max_rate_to_keep_window"`
+	WindowAdjustPerRetry float64 `json:"window_adjust_per_retry" yaml:"window_adjust_per_retry"`
+	DeviationWindowRatio float64 `json:"deviation_window_ratio" yaml:"deviation_window_ratio"` // window widened per deviation of ratings
+
+	// window growth
+	// In WindowGrowthRound, a retry is a matching round the party waited.
+	// In WindowGrowthTime, a retry is every WaitStepSec seconds the party waited, up to MaxWaitSec.
+	WindowGrowth string  `json:"window_growth" yaml:"window_growth"`
+	WaitStepSec  float64 `json:"wait_step_sec" yaml:"wait_step_sec"`
+	MaxWaitSec   float64 `json:"max_wait_sec" yaml:"max_wait_sec"` // 0 means no limit
 
 	// filter
-	ScoreBoundFilter     string    `json:"score_bound_filter"`
-	ScoreModRatio        []float64 `json:"score_mod_ratio"`
-	MatchingWindowFilter string    `json:"matching_window_filter"`
+	Filter               string       `json:"filter" yaml:"filter"` // name of the registered filter; empty for the built-in filter
+	FilterParams         FilterParams `json:"filter_params" yaml:"filter_params"`
+	ScoreBoundFilter     string       `json:"score_bound_filter" yaml:"score_bound_filter"`
+	ScoreModRatio        []float64    `json:"score_mod_ratio" yaml:"score_mod_ratio"`
+	MatchingWindowFilter string       `json:"matching_window_filter" yaml:"matching_window_filter"`
 
 	// group
-	MinNumToCreateGroup    int `json:"min_num_to_create_group"`
-	MaxNumToCreateGroup    int `json:"max_num_to_create_group"`
-	NumPlayerToCreateGroup int `json:"num_player_to_create_group"`
-	NumRoundToCreateGroup  int `json:"num_round_to_create_group"`
+	MinNumToCreateGroup    int `json:"min_num_to_create_group" yaml:"min_num_to_create_group"`
+	MaxNumToCreateGroup    int `json:"max_num_to_create_group" yaml:"max_num_to_create_group"`
+	NumPlayerToCreateGroup int `json:"num_player_to_create_group" yaml:"num_player_to_create_group"`
+	NumRoundToCreateGroup  int `json:"num_round_to_create_group" yaml:"num_round_to_create_group"`
+
+	// duplicate
+	// DuplicateReject rejects a party which has a queued player, and DuplicateReplace
+	// removes the party which has the player, with all its members, before adding the new one.
+	DuplicatePolicy string `json:"duplicate_policy" yaml:"duplicate_policy"`
+
+	// statistics
+	StatWindowSec []int `json:"stat_window_sec" yaml:"stat_window_sec"` // periods (second) of sliding windows in State
+
+	// team
+	// If empty, a group has 2 teams and number of players in each team cannot differ more than 1.
+	Teams []TeamLayout `json:"teams" yaml:"teams"`
+
+	// role
+	// Composition of roles of every team. Roles not in the rules are not limited.
+	// If empty, roles of players are not considered.
+	Roles []RoleRule `json:"roles" yaml:"roles"`
+
+	// constraint
+	// Players in a group must satisfy all the constraints regardless of their scores.
+	Constraints []ConstraintConfig `json:"constraints" yaml:"constraints"`
+
+	// latency
+	// Players in a group must have a common datacenter where latencies of all of them are
+	// within the max latency. The max latency of a party is relaxed while it retries, up to LatencyLimit.
+	MaxLatency            float64 `json:"max_latency" yaml:"max_latency"` // millisecond; 0 means latencies are not considered
+	LatencyAdjustPerRetry float64 `json:"latency_adjust_per_retry" yaml:"latency_adjust_per_retry"`
+	LatencyLimit          float64 `json:"latency_limit" yaml:"latency_limit"` // 0 means no limit
 }
 
+// TeamLayout describes the size of a team in a group.
+type TeamLayout struct {
+	MinSize int        `json:"min_size" yaml:"min_size"`
+	MaxSize int        `json:"max_size" yaml:"max_size"` // 0 means no limit
+	Roles   []RoleRule `json:"roles" yaml:"roles"`       // composition of roles of the team; overrides Config.Roles
+}
+
+// teamLayouts returns the layout of teams and whether the teams must be balanced by head count.
+func (c *Config) teamLayouts() ([]TeamLayout, bool) {
+	if len(c.Teams) == 0 {
+		return []TeamLayout{{}, {}}, true
+	}
+	return c.Teams, false
+}
+
+// modes of window growth
+const (
+	WindowGrowthRound = "round"
+	WindowGrowthTime  = "time"
+)
+
+// policies for a queued player added again
+const (
+	DuplicateReject  = "reject"
+	DuplicateReplace = "replace"
+)
+
 var defaultModRatio = []float64{1.0, 1.0, 1.0, 1.0, 1.0, 0.8, 0.6, 0.4, 0.2, 0.2, 0.2, 0.2, 0.2, 0.2, 0.2, 0.2, 0.2, 0.2, 0.2, 0.2}
 
 // DefaultConfig returns the predefined configuration.
@@ -34,12 +105,212 @@ func DefaultConfig() *Config {
 		MinRateToKeepWindow:    0.85,
 		MaxRateToKeepWindow:    0.95,
 		WindowAdjustPerRetry:   0.5,
+		DeviationWindowRatio:   1.0,
+		WindowGrowth:           WindowGrowthRound,
+		WaitStepSec:            10.0,
+		FilterParams:           DefaultFilterParams(),
 		ScoreBoundFilter:       "curve",
-		ScoreModRatio:          defaultModRatio,
+		ScoreModRatio:          slices.Clone(defaultModRatio),
 		MatchingWindowFilter:   "calculated",
 		MinNumToCreateGroup:    10,
 		MaxNumToCreateGroup:    16,
 		NumPlayerToCreateGroup: 40,
 		NumRoundToCreateGroup:  2,
+		DuplicatePolicy:        DuplicateReject,
+		StatWindowSec:          []int{60, 300, 3600},
+	}
+}
+
+// Validate checks the configuration and returns an error joining every problem found.
+func (c *Config) Validate() error {
+	_, err := c.validate()
+	return err
+}
+
+// validate checks the configuration and returns the filter built while checking.
+func (c *Config) validate() (Filter, error) {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidConfig}, args...)...))
+	}
+
+	// match window
+	if c.MinMatchWindow < 0.0 {
+		invalid("min_match_window (%v) is negative", c.MinMatchWindow)
+	}
+	if c.MinMatchWindow > c.MaxMatchWindow {
+		invalid("min_match_window (%v) is greater than max_match_window (%v)", c.MinMatchWindow, c.MaxMatchWindow)
+	}
+	if c.InitMatchWindow < c.MinMatchWindow || c.InitMatchWindow > c.MaxMatchWindow {
+		invalid("init_match_window (%v) is out of [min_match_window, max_match_window]", c.InitMatchWindow)
+	}
+	if c.WindowAdjustStep < 0.0 {
+		invalid("window_adjust_step (%v) is negative", c.WindowAdjustStep)
+	}
+	if c.MinRateToKeepWindow < 0.0 || c.MaxRateToKeepWindow > 1.0 {
+		invalid("rates to keep window [%v, %v] are out of [0, 1]", c.MinRateToKeepWindow, c.MaxRateToKeepWindow)
+	}
+	if c.MinRateToKeepWindow > c.MaxRateToKeepWindow {
+		invalid("min_rate_to_keep_window (%v) is greater than max_rate_to_keep_window (%v)", c.MinRateToKeepWindow, c.MaxRateToKeepWindow)
+	}
+	if c.WindowAdjustPerRetry < 0.0 {
+		invalid("window_adjust_per_retry (%v) is negative", c.WindowAdjustPerRetry)
+	}
+	if c.DeviationWindowRatio < 0.0 {
+		invalid("deviation_window_ratio (%v) is negative", c.DeviationWindowRatio)
+	}
+
+	// window growth
+	switch c.WindowGrowth {
+	case WindowGrowthRound, "":
+	case WindowGrowthTime:
+		if c.WaitStepSec <= 0.0 {
+			invalid("wait_step_sec (%v) is not positive", c.WaitStepSec)
+		}
+	default:
+		invalid("unknown window_growth %q", c.WindowGrowth)
+	}
+	if c.MaxWaitSec < 0.0 {
+		invalid("max_wait_sec (%v) is negative", c.MaxWaitSec)
+	}
+
+	// filter; the built-in filter reports each problem
+	filter, err := NewFilter(c)
+	if err != nil {
+		for _, e := range flattenErrors(err) {
+			errs = append(errs, fmt.Errorf("%w: %w", ErrInvalidConfig, e))
+		}
+	}
+
+	// group
+	if c.MinNumToCreateGroup <= 0 {
+		invalid("min_num_to_create_group (%v) is not positive", c.MinNumToCreateGroup)
+	}
+	if c.MinNumToCreateGroup > c.MaxNumToCreateGroup {
+		invalid("min_num_to_create_group (%v) is greater than max_num_to_create_group (%v)", c.MinNumToCreateGroup, c.MaxNumToCreateGroup)
+	}
+	if c.NumPlayerToCreateGroup < 0 {
+		invalid("num_player_to_create_group (%v) is negative", c.NumPlayerToCreateGroup)
+	}
+	if c.NumRoundToCreateGroup < 0 {
+		invalid("num_round_to_create_group (%v) is negative", c.NumRoundToCreateGroup)
+	}
+
+	// duplicate
+	switch c.DuplicatePolicy {
+	case DuplicateReject, DuplicateReplace, "":
+	default:
+		invalid("unknown duplicate_policy %q", c.DuplicatePolicy)
+	}
+
+	// statistics
+	for i, sec := range c.StatWindowSec {
+		if sec <= 0 {
+			invalid("stat_window_sec[%d] (%v) is not positive", i, sec)
+		}
+	}
+
+	// team
+	if len(c.Teams) == 1 {
+		invalid("teams has only one team")
+	}
+	sumMin, sumMax := 0, 0
+	for i, t := range c.Teams {
+		if t.MinSize < 0 || t.MaxSize < 0 {
+			invalid("teams[%d] has negative size", i)
+		}
+		if t.MaxSize > 0 && t.MinSize > t.MaxSize {
+			invalid("teams[%d].min_size (%v) is greater than max_size (%v)", i, t.MinSize, t.MaxSize)
+		}
+		sumMin += t.MinSize
+		if sumMax >= 0 && t.MaxSize > 0 {
+			sumMax += t.MaxSize
+		} else {
+			// no limit
+			sumMax = -1
+		}
+	}
+	if sumMin > c.MaxNumToCreateGroup {
+		invalid("sum of teams' min_size (%v) is greater than max_num_to_create_group (%v)", sumMin, c.MaxNumToCreateGroup)
+	}
+	if len(c.Teams) > 0 && sumMax >= 0 && sumMax < c.MinNumToCreateGroup {
+		invalid("sum of teams' max_size (%v) is less than min_num_to_create_group (%v)", sumMax, c.MinNumToCreateGroup)
+	}
+
+	// role
+	validateRoles := func(name string, rules []RoleRule, maxSize int) {
+		sumMin := 0
+		for i, r := range rules {
+			if r.Role == "" {
+				invalid("%s[%d].role is empty", name, i)
+			}
+			if slices.ContainsFunc(rules[:i], func(t RoleRule) bool { return t.Role == r.Role }) {
+				invalid("%s[%d].role %q is duplicated", name, i, r.Role)
+			}
+			if r.Min < 0 || r.Max < 0 {
+				invalid("%s[%d] has negative count", name, i)
+			}
+			if r.Max > 0 && r.Min > r.Max {
+				invalid("%s[%d].min (%v) is greater than max (%v)", name, i, r.Min, r.Max)
+			}
+			sumMin += r.Min
+		}
+		if maxSize > 0 && sumMin > maxSize {
+			invalid("sum of %s' min (%v) is greater than the team size (%v)", name, sumMin, maxSize)
+		}
+	}
+	if len(c.Teams) == 0 {
+		validateRoles("roles", c.Roles, (c.MaxNumToCreateGroup+1)/2)
+	} else {
+		validateRoles("roles", c.Roles, 0)
+	}
+	for i, t := range c.Teams {
+		validateRoles(fmt.Sprintf("teams[%d].roles", i), t.Roles, t.MaxSize)
+	}
+
+	// constraint
+	for i, cc := range c.Constraints {
+		if _, err := newConstraint(cc); err != nil {
+			invalid("constraints[%d]: %v", i, err)
+		}
+		if cc.Attribute == "" {
+			invalid("constraints[%d].attribute is empty", i)
+		}
+		if cc.MaxDiff < 0.0 {
+			invalid("constraints[%d].max_diff (%v) is negative", i, cc.MaxDiff)
+		}
+	}
+
+	// latency
+	if c.MaxLatency < 0.0 {
+		invalid("max_latency (%v) is negative", c.MaxLatency)
+	}
+	if c.LatencyAdjustPerRetry < 0.0 {
+		invalid("latency_adjust_per_retry (%v) is negative", c.LatencyAdjustPerRetry)
+	}
+	if c.LatencyLimit < 0.0 {
+		invalid("latency_limit (%v) is negative", c.LatencyLimit)
+	}
+	if c.LatencyLimit > 0.0 && c.LatencyLimit < c.MaxLatency {
+		invalid("latency_limit (%v) is less than max_latency (%v)", c.LatencyLimit, c.MaxLatency)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return filter, nil
+}
+
+// flattenErrors returns the errors joined in err, recursively.
+func flattenErrors(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, flattenErrors(e)...)
 	}
+	return errs
 }
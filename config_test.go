@@ -0,0 +1,78 @@
+package matchqueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr int // number of problems
+	}{
+		{"default", func(c *Config) {}, 0},
+		{"group size", func(c *Config) { c.MinNumToCreateGroup, c.MaxNumToCreateGroup = 10, 8 }, 1},
+		{"match window", func(c *Config) { c.MinMatchWindow, c.MaxMatchWindow = 60.0, 50.0 }, 2},
+		{"rate", func(c *Config) { c.MinRateToKeepWindow, c.MaxRateToKeepWindow = 0.9, 0.8 }, 1},
+		{"empty ratio", func(c *Config) { c.ScoreModRatio = nil }, 1},
+		{"zero filter params", func(c *Config) { c.FilterParams = FilterParams{} }, 0},
+		{"unknown filter", func(c *Config) { c.ScoreBoundFilter = "curvy" }, 1},
+		{"custom filter", func(c *Config) { c.Filter = "not-registered"; c.ScoreModRatio = nil }, 1},
+		{"duplicate policy", func(c *Config) { c.DuplicatePolicy = "ignore" }, 1},
+		{"teams", func(c *Config) { c.Teams = []TeamLayout{{MinSize: 5, MaxSize: 4}, {MinSize: 12}} }, 2},
+		{"unreachable group size", func(c *Config) {
+			c.Teams = []TeamLayout{{MinSize: 1, MaxSize: 1}, {MinSize: 1, MaxSize: 1}, {MinSize: 1, MaxSize: 1}, {MinSize: 1, MaxSize: 1}}
+		}, 1},
+		{"roles", func(c *Config) {
+			c.Roles = []RoleRule{{Role: "tank", Min: 2, Max: 1}, {Role: "tank"}, {Role: "healer", Min: 8}}
+		}, 3},
+		{"team roles", func(c *Config) {
+			c.MinNumToCreateGroup = 5
+			c.Teams = []TeamLayout{{MinSize: 1, MaxSize: 1}, {MinSize: 4, MaxSize: 4, Roles: []RoleRule{{Role: "", Min: -1}}}}
+		}, 2},
+		{"constraints", func(c *Config) {
+			c.Constraints = []ConstraintConfig{{Type: "same", Attribute: "platform"}, {Type: ConstraintRange, MaxDiff: -1}}
+		}, 3},
+		{"deviation window ratio", func(c *Config) { c.DeviationWindowRatio = -1.0 }, 1},
+		{"latency", func(c *Config) { c.MaxLatency, c.LatencyAdjustPerRetry, c.LatencyLimit = 100.0, -1.0, 80.0 }, 2},
+		{"multiple", func(c *Config) {
+			c.MinNumToCreateGroup, c.MaxNumToCreateGroup = 10, 8
+			c.ScoreModRatio = []float64{}
+			c.MatchingWindowFilter = "calculate"
+		}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := DefaultConfig()
+			tt.modify(conf)
+
+			err := conf.Validate()
+			if tt.wantErr == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), tt.wantErr)
+		})
+	}
+}
+
+func TestNewQueue(t *testing.T) {
+	q, err := NewQueue(DefaultConfig())
+	assert.NoError(t, err)
+	assert.NotNil(t, q)
+
+	conf := DefaultConfig()
+	conf.MinMatchWindow = -1.0
+	q, err = NewQueue(conf)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Nil(t, q)
+
+	// a config without filter params uses the defaults
+	conf = DefaultConfig()
+	conf.FilterParams = FilterParams{}
+	assert.NotPanics(t, func() { New(conf) })
+}
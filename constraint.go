@@ -0,0 +1,207 @@
+package matchqueue
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Constraint decides whether players can be in the same group regardless of their scores.
+// Constraints are consulted when a party is added, when parties are matched
+// and when parties are gathered into a group.
+type Constraint interface {
+	// Allow reports whether all the players can be in the same group.
+	Allow(players []*Player) bool
+}
+
+// ConstraintFunc is an adapter to use a function as a Constraint.
+type ConstraintFunc func(players []*Player) bool
+
+func (f ConstraintFunc) Allow(players []*Player) bool {
+	return f(players)
+}
+
+// types of built-in constraints
+const (
+	ConstraintEqual     = "equal"
+	ConstraintIntersect = "intersect"
+	ConstraintRange     = "range"
+)
+
+// ConstraintConfig defines a built-in constraint on an attribute of players.
+// Players who do not have the attribute are not restricted by the constraint.
+type ConstraintConfig struct {
+	Type      string  `json:"type" yaml:"type"` // ConstraintEqual, ConstraintIntersect or ConstraintRange
+	Attribute string  `json:"attribute" yaml:"attribute"`
+	MaxDiff   float64 `json:"max_diff" yaml:"max_diff"` // maximum difference of values for ConstraintRange
+}
+
+// newConstraint creates the built-in constraint of the configuration.
+func newConstraint(cc ConstraintConfig) (Constraint, error) {
+	switch cc.Type {
+	case ConstraintEqual:
+		return Equal(cc.Attribute), nil
+	case ConstraintIntersect:
+		return Intersect(cc.Attribute), nil
+	case ConstraintRange:
+		return Range(cc.Attribute, cc.MaxDiff), nil
+	}
+	return nil, fmt.Errorf("unknown constraint type %q", cc.Type)
+}
+
+// Equal requires players to have the same value of the attribute, such as a platform or a client version.
+// A string and a set of strings are compared as sets.
+func Equal(attr string) Constraint {
+	return ConstraintFunc(func(players []*Player) bool {
+		var first any
+		for _, pl := range players {
+			v, ok := pl.Attributes[attr]
+			if !ok {
+				continue
+			}
+			if first == nil {
+				first = v
+			} else if !attributeEqual(first, v) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Intersect requires sets of the attribute of players to have a common value, such as map pools or languages.
+func Intersect(attr string) Constraint {
+	return ConstraintFunc(func(players []*Player) bool {
+		var common []string
+		found := false
+		for _, pl := range players {
+			v, ok := pl.Attributes[attr]
+			if !ok {
+				continue
+			}
+			set, _ := attributeStrings(v)
+			if !found {
+				common, found = slices.Clone(set), true
+			} else {
+				common = slices.DeleteFunc(common, func(s string) bool { return !slices.Contains(set, s) })
+			}
+			if len(common) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Range requires numeric values of the attribute of players to differ at most maxDiff.
+// Values which are not numbers are ignored.
+func Range(attr string, maxDiff float64) Constraint {
+	return ConstraintFunc(func(players []*Player) bool {
+		lowest, highest := math.Inf(1), math.Inf(-1)
+		for _, pl := range players {
+			v, ok := attributeNumber(pl.Attributes[attr])
+			if !ok {
+				continue
+			}
+			lowest, highest = min(lowest, v), max(highest, v)
+			if highest-lowest > maxDiff {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// attributeNumber converts the value of an attribute into a number.
+func attributeNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0.0, false
+}
+
+// attributeStrings converts the value of an attribute into a set of strings.
+// Values decoded from JSON, such as []any, are also converted.
+func attributeStrings(v any) ([]string, bool) {
+	switch s := v.(type) {
+	case string:
+		return []string{s}, true
+	case []string:
+		return s, true
+	case []any:
+		set := make([]string, 0, len(s))
+		for _, e := range s {
+			str, ok := e.(string)
+			if !ok {
+				return nil, false
+			}
+			set = append(set, str)
+		}
+		return set, true
+	}
+	return nil, false
+}
+
+func attributeEqual(a, b any) bool {
+	if x, ok := attributeNumber(a); ok {
+		y, ok := attributeNumber(b)
+		return ok && x == y
+	}
+
+	x, xok := attributeStrings(a)
+	y, yok := attributeStrings(b)
+	if !xok || !yok {
+		return false
+	}
+	for _, s := range x {
+		if !slices.Contains(y, s) {
+			return false
+		}
+	}
+	for _, s := range y {
+		if !slices.Contains(x, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildConstraints returns the constraints given by options followed by the constraints of the configuration.
+func (q *queue) buildConstraints(conf *Config) ([]Constraint, error) {
+	constraints := slices.Clone(q.optConstraints)
+	for i, cc := range conf.Constraints {
+		c, err := newConstraint(cc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: constraints[%d]: %w", ErrInvalidConfig, i, err)
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints, nil
+}
+
+// allow checks if the players can be in the same group under all constraints of the queue.
+func (q *queue) allow(players []*Player) bool {
+	for _, c := range q.constraints {
+		if !c.Allow(players) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowParties checks if players of the parties can be in the same group.
+func (q *queue) allowParties(parties ...*party) bool {
+	if q == nil || len(q.constraints) == 0 {
+		return true
+	}
+	return q.allow(partyPlayers(parties))
+}
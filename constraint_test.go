@@ -0,0 +1,100 @@
+package matchqueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Constraint(t *testing.T) {
+	newPlayers := func(attr string, values ...any) []*Player {
+		var players []*Player
+		for i, v := range values {
+			pl := &Player{ID: PlayerID(i + 1)}
+			if v != nil {
+				pl.Attributes = map[string]any{attr: v}
+			}
+			players = append(players, pl)
+		}
+		return players
+	}
+
+	tests := []struct {
+		name       string
+		constraint Constraint
+		players    []*Player
+		want       bool
+	}{
+		{"equal", Equal("platform"), newPlayers("platform", "pc", "pc"), true},
+		{"not equal", Equal("platform"), newPlayers("platform", "pc", "console"), false},
+		{"equal wildcard", Equal("platform"), newPlayers("platform", nil, "pc", nil, "pc"), true},
+		{"equal number", Equal("version"), newPlayers("version", 3, 3.0), true},
+		{"equal set", Equal("dlc"), newPlayers("dlc", []string{"a", "b"}, []any{"b", "a"}), true},
+		{"intersect", Intersect("maps"), newPlayers("maps", []string{"dust", "nuke"}, []string{"nuke", "mirage"}, "nuke"), true},
+		{"no intersection", Intersect("maps"), newPlayers("maps", []string{"dust", "nuke"}, []string{"nuke"}, []string{"dust"}), false},
+		{"intersect wildcard", Intersect("maps"), newPlayers("maps", nil, nil), true},
+		{"range", Range("level", 5), newPlayers("level", 10, 15.0, 12), true},
+		{"out of range", Range("level", 5), newPlayers("level", 10, 12, 16), false},
+		{"range not number", Range("level", 5), newPlayers("level", 10, "high", 15), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.constraint.Allow(tt.players))
+		})
+	}
+}
+
+func Test_queue_constraints(t *testing.T) {
+	conf := DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.InitMatchWindow = conf.MaxMatchWindow
+	conf.Constraints = []ConstraintConfig{{Type: ConstraintEqual, Attribute: "platform"}}
+
+	// players of odd levels and even levels are kept apart
+	parity := ConstraintFunc(func(players []*Player) bool {
+		for _, pl := range players {
+			if pl.Attributes["level"].(int)%2 != players[0].Attributes["level"].(int)%2 {
+				return false
+			}
+		}
+		return true
+	})
+
+	q := New(conf, WithConstraints(parity)).(*queue)
+	player := func(id PlayerID, platform string, level int) *Player {
+		return &Player{ID: id, Score: 25.0, Attributes: map[string]any{"platform": platform, "level": level}}
+	}
+
+	assert.ErrorIs(t, q.AddPlayer([]*Player{player(1, "pc", 1), player(2, "console", 1)}), ErrConstraintViolated)
+	assert.Empty(t, q.parties)
+
+	for _, pl := range []*Player{player(1, "pc", 1), player(2, "console", 1), player(3, "pc", 2), player(4, "console", 3), player(5, "pc", 4)} {
+		require.NoError(t, q.AddPlayer([]*Player{pl}))
+	}
+
+	groups, err := q.ProcCreate()
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+
+	var pairs [][]PlayerID
+	for _, g := range groups {
+		var ids []PlayerID
+		for _, gp := range g.Parties {
+			ids = append(ids, gp.Leader)
+		}
+		pairs = append(pairs, ids)
+	}
+	assert.ElementsMatch(t, [][]PlayerID{{2, 4}, {3, 5}}, pairs)
+
+	// attributes are kept in a snapshot
+	q = New(conf, WithConstraints(parity)).(*queue)
+	require.NoError(t, q.AddPlayer([]*Player{player(1, "pc", 1)}))
+	data, err := q.Snapshot()
+	require.NoError(t, err)
+
+	restored := New(conf).(*queue)
+	require.NoError(t, restored.Restore(data))
+	assert.Equal(t, "pc", restored.parties[0].players[0].Attributes["platform"])
+}
@@ -1,8 +1,38 @@
 package matchqueue
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrNotInitialized  = errors.New("not initialized")
-	ErrNotEnoughPlayer = errors.New("not enough player")
+	ErrNotInitialized      = errors.New("not initialized")
+	ErrNotEnoughPlayer     = errors.New("not enough player")
+	ErrRunnerStarted       = errors.New("runner already started")
+	ErrRunnerNotRunning    = errors.New("runner not running")
+	ErrSnapshotVersion     = errors.New("unsupported snapshot version")
+	ErrUnknownFilter       = errors.New("unknown filter")
+	ErrInvalidConfig       = errors.New("invalid config")
+	ErrUnknownConfigFormat = errors.New("unknown config format")
+	ErrUnknownQueue        = errors.New("unknown queue")
+	ErrNoQueue             = errors.New("no queue given")
+	ErrQueueExists         = errors.New("queue already exists")
+	ErrPlayerQueued        = errors.New("player already queued")
+	ErrPlayerNotQueued     = errors.New("player not queued")
+	ErrConstraintViolated  = errors.New("players violate constraints")
 )
+
+// DuplicatePlayerError is returned when a player being added is already queued.
+// It matches ErrPlayerQueued with errors.Is.
+type DuplicatePlayerError struct {
+	Player PlayerID // the duplicated player
+	Leader PlayerID // leader of the party which already has the player
+}
+
+func (e *DuplicatePlayerError) Error() string {
+	return fmt.Sprintf("player %d already queued in the party of %d", e.Player, e.Leader)
+}
+
+func (e *DuplicatePlayerError) Unwrap() error {
+	return ErrPlayerQueued
+}
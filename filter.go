@@ -1,6 +1,11 @@
 package matchqueue
 
-import "math"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
 
 const (
 	defaultScoreInitial float64 = 25.0
@@ -8,55 +13,196 @@ const (
 	defaultScoreRange   float64 = defaultScoreInitial
 	scoreBoundMin       float64 = 0.0
 	scoreBoundMax       float64 = 100.0
-	scoreBoundMid       float64 = (scoreBoundMax + scoreBoundMin) / 2.0
-	scoreBoundRange     float64 = (scoreBoundMax - scoreBoundMin) / 2.0
 	m2DivPi             float64 = 2.0 / math.Pi
 	mPiDiv2             float64 = math.Pi / 2.0
 	slope               float64 = 3.0
 
 	partyScoreMod float64 = 1.5
+
+	// name of the built-in filter
+	DefaultFilterName = "default"
 )
 
-// implementation of matchFilter
+type (
+	// Filter calculates the matching factors of parties.
+	Filter interface {
+		// AdjustPartyScore adjusts the average score of a party of cnt players.
+		AdjustPartyScore(cnt int, score float64) float64
+
+		// BoundScore maps the score into the score boundary of the queue.
+		BoundScore(score float64) float64
+
+		// ModifyScore modifies the bound score as the party waits for retry rounds.
+		ModifyScore(retry int, score float64) float64
+
+		// AdjustWindow adjusts the queue's match window for a party of the modified score.
+		AdjustWindow(score, window float64) float64
+	}
+
+	// FilterFactory creates a filter for the configuration.
+	FilterFactory func(conf *Config) (Filter, error)
+)
+
+var filterRegistry = struct {
+	sync.RWMutex
+	factories map[string]FilterFactory
+}{
+	factories: map[string]FilterFactory{
+		DefaultFilterName: func(conf *Config) (Filter, error) { return newMatchFilter(conf) },
+	},
+}
+
+// RegisterFilter makes a filter available by the name.
+// Queues use the filter if Config.Filter is the name.
+// It panics if the name is already registered or the factory is nil.
+func RegisterFilter(name string, factory FilterFactory) {
+	filterRegistry.Lock()
+	defer filterRegistry.Unlock()
+
+	if factory == nil {
+		panic("matchqueue: RegisterFilter factory is nil")
+	}
+	if _, ok := filterRegistry.factories[name]; ok {
+		panic("matchqueue: RegisterFilter called twice for filter " + name)
+	}
+	filterRegistry.factories[name] = factory
+}
+
+// NewFilter creates the filter named by conf.Filter.
+// If the name is empty, the built-in filter is created.
+func NewFilter(conf *Config) (Filter, error) {
+	name := conf.Filter
+	if name == "" {
+		name = DefaultFilterName
+	}
+
+	filterRegistry.RLock()
+	factory, ok := filterRegistry.factories[name]
+	filterRegistry.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFilter, name)
+	}
+	return factory(conf)
+}
+
+// FilterParams are the parameters of the built-in filter.
+// The zero value, as in a Config without filter params, is DefaultFilterParams.
+// Otherwise all values are used as they are, including zeros; start from DefaultFilterParams to keep the defaults.
+// LoadConfig keeps the defaults of the parameters which are not given in the file.
+type FilterParams struct {
+	ScoreMid      float64 `json:"score_mid" yaml:"score_mid"`             // score which is mapped to the middle of the boundary
+	ScoreRange    float64 `json:"score_range" yaml:"score_range"`         // distance from ScoreMid which is mapped to the edge of the boundary
+	BoundMin      float64 `json:"bound_min" yaml:"bound_min"`             // lower limit of the bound score
+	BoundMax      float64 `json:"bound_max" yaml:"bound_max"`             // upper limit of the bound score
+	Slope         float64 `json:"slope" yaml:"slope"`                     // slope of the curve filter
+	PartyScoreMod float64 `json:"party_score_mod" yaml:"party_score_mod"` // multiplier of a party's score
+}
+
+// DefaultFilterParams returns the predefined parameters of the built-in filter.
+func DefaultFilterParams() FilterParams {
+	return FilterParams{
+		ScoreMid:      defaultScoreMid,
+		ScoreRange:    defaultScoreRange,
+		BoundMin:      scoreBoundMin,
+		BoundMax:      scoreBoundMax,
+		Slope:         slope,
+		PartyScoreMod: partyScoreMod,
+	}
+}
+
+// orDefault returns DefaultFilterParams if the parameters are the zero value.
+func (fp FilterParams) orDefault() FilterParams {
+	if fp == (FilterParams{}) {
+		return DefaultFilterParams()
+	}
+	return fp
+}
+
+// validate returns an error joining every problem of the parameters.
+func (fp FilterParams) validate() error {
+	var errs []error
+	if fp.BoundMin >= fp.BoundMax {
+		errs = append(errs, fmt.Errorf("filter_params.bound_min (%v) is not less than filter_params.bound_max (%v)", fp.BoundMin, fp.BoundMax))
+	}
+	if fp.ScoreRange <= 0.0 || fp.Slope <= 0.0 {
+		errs = append(errs, errors.New("filter_params.score_range or slope is not positive"))
+	}
+	return errors.Join(errs...)
+}
+
+func (fp FilterParams) boundMid() float64 {
+	return (fp.BoundMax + fp.BoundMin) / 2.0
+}
+
+func (fp FilterParams) boundRange() float64 {
+	return (fp.BoundMax - fp.BoundMin) / 2.0
+}
+
+// implementation of Filter
 type matchFilter struct {
+	params FilterParams
+
+	windowNorm float64 // divisor of the calculated window; the derivative of the curve at ScoreMid
+
 	scoreBoundFilter     func(float64) float64
 	scoreModFilter       []func(float64) float64
 	matchingWindowFilter func(float64, float64) float64
 }
 
-func newMatchFilter(scoreBoundFilter, matchingWindowFilter string, scoreModRatio []float64) *matchFilter {
-	f := &matchFilter{}
+var _ Filter = new(matchFilter)
+
+// newMatchFilter creates the built-in filter.
+// Its functions are selected by conf.ScoreBoundFilter and conf.MatchingWindowFilter.
+// It returns an error joining every problem of the configuration.
+func newMatchFilter(conf *Config) (*matchFilter, error) {
+	f := &matchFilter{params: conf.FilterParams.orDefault()}
+	var errs []error
 
-	switch scoreBoundFilter {
+	switch conf.ScoreBoundFilter {
 	case "curve":
-		f.scoreBoundFilter = scoreBoundFilterCurve
-	case "simple":
-		fallthrough
+		f.scoreBoundFilter = f.scoreBoundFilterCurve
+	case "simple", "":
+		f.scoreBoundFilter = f.scoreBoundFilterSimple
 	default:
-		f.scoreBoundFilter = scoreBoundFilterSimple
+		errs = append(errs, fmt.Errorf("%w: score bound filter %q", ErrUnknownFilter, conf.ScoreBoundFilter))
 	}
 
-	switch matchingWindowFilter {
+	switch conf.MatchingWindowFilter {
 	case "calculated":
-		f.matchingWindowFilter = matchingWindowSizeCalculated
-	case "simple":
-		fallthrough
-	default:
+		f.matchingWindowFilter = f.matchingWindowSizeCalculated
+	case "simple", "":
 		f.matchingWindowFilter = matchingWindowSizeSimple
+	default:
+		errs = append(errs, fmt.Errorf("%w: matching window filter %q", ErrUnknownFilter, conf.MatchingWindowFilter))
+	}
+
+	// ModifyScore requires at least one ratio
+	if len(conf.ScoreModRatio) == 0 {
+		errs = append(errs, errors.New("score_mod_ratio is empty"))
+	}
+	if err := f.params.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
-	for _, ratio := range scoreModRatio {
+	f.windowNorm = f.derivativeBound(f.params.ScoreMid)
+
+	mid := f.params.boundMid()
+	for _, ratio := range conf.ScoreModRatio {
 		f.scoreModFilter = append(f.scoreModFilter, func(score float64) float64 {
-			return scoreBoundMid + (score-scoreBoundMid)*ratio
+			return mid + (score-mid)*ratio
 		})
 	}
 
-	return f
+	return f, nil
 }
 
 func (f *matchFilter) AdjustPartyScore(cnt int, score float64) float64 {
 	if cnt > 1 {
-		return partyFilter(score)
+		return f.params.PartyScoreMod * score
 	}
 	return score
 }
@@ -74,20 +220,24 @@ func (f *matchFilter) AdjustWindow(score, window float64) float64 {
 }
 
 // filters
-func scoreBoundFilterSimple(score float64) float64 {
-	return clamp(2.0*score, scoreBoundMin, scoreBoundMax)
+func (f *matchFilter) scoreBoundFilterSimple(score float64) float64 {
+	p := f.params
+	return clamp(p.boundMid()+(score-p.ScoreMid)*p.boundRange()/p.ScoreRange, p.BoundMin, p.BoundMax)
 }
 
-func scoreBoundFilterCurve(score float64) float64 {
-	return scoreBoundMid + scoreBoundRange*m2DivPi*math.Atan((score-defaultScoreMid)*(slope/defaultScoreRange))
+func (f *matchFilter) scoreBoundFilterCurve(score float64) float64 {
+	p := f.params
+	return p.boundMid() + p.boundRange()*m2DivPi*math.Atan((score-p.ScoreMid)*(p.Slope/p.ScoreRange))
 }
 
-func inverseBound(t float64) float64 {
-	return defaultScoreMid + (defaultScoreRange/slope)*math.Tan((t-scoreBoundMid)*mPiDiv2/scoreBoundRange)
+func (f *matchFilter) inverseBound(t float64) float64 {
+	p := f.params
+	return p.ScoreMid + (p.ScoreRange/p.Slope)*math.Tan((t-p.boundMid())*mPiDiv2/p.boundRange())
 }
 
-func derivativeBound(score float64) float64 {
-	return (m2DivPi * slope * scoreBoundRange / defaultScoreRange) * (1.0 / (1.0 + math.Pow((slope*(score-defaultScoreMid)/defaultScoreRange), 2)))
+func (f *matchFilter) derivativeBound(score float64) float64 {
+	p := f.params
+	return (m2DivPi * p.Slope * p.boundRange() / p.ScoreRange) * (1.0 / (1.0 + math.Pow((p.Slope*(score-p.ScoreMid)/p.ScoreRange), 2)))
 }
 
 // window size for bounding domain
@@ -95,12 +245,6 @@ func matchingWindowSizeSimple(score, window float64) float64 {
 	return window
 }
 
-func matchingWindowSizeCalculated(score, window float64) float64 {
-	// 3.82 is calculated considering base filter function with slope 3.0
-	return window * derivativeBound(inverseBound(score)) / 3.82
-}
-
-// party filter
-func partyFilter(score float64) float64 {
-	return partyScoreMod * score
+func (f *matchFilter) matchingWindowSizeCalculated(score, window float64) float64 {
+	return window * f.derivativeBound(f.inverseBound(score)) / f.windowNorm
 }
@@ -19,7 +19,7 @@ func Test_matchFilter_AdjustPartyScore(t *testing.T) {
 		}
 	)
 
-	f := &matchFilter{}
+	f := testMatchFilter("", "", defaultModRatio)
 
 	tests := []test{
 		{"big party", args{5, 10.0}, 15.0},
@@ -47,7 +47,7 @@ func Test_matchFilter_BoundScore(t *testing.T) {
 	)
 
 	t.Run("simple", func(t *testing.T) {
-		f := newMatchFilter("simple", "", nil)
+		f := testMatchFilter("simple", "", defaultModRatio)
 
 		tests := []test{
 			{"in range", args{10.0}, 20.0},
@@ -63,7 +63,7 @@ func Test_matchFilter_BoundScore(t *testing.T) {
 	})
 
 	t.Run("curve", func(t *testing.T) {
-		f := newMatchFilter("curve", "", nil)
+		f := testMatchFilter("curve", "", defaultModRatio)
 
 		tests := []test{
 			{"in range", args{10.0}, 16.141446721709528},
@@ -80,7 +80,7 @@ func Test_matchFilter_BoundScore(t *testing.T) {
 }
 
 func Test_matchFilter_ModifyScore(t *testing.T) {
-	f := newMatchFilter("", "", defaultModRatio)
+	f := testMatchFilter("", "", defaultModRatio)
 
 	type args struct {
 		retry int
@@ -119,7 +119,7 @@ func Test_matchFilter_AdjustWindow(t *testing.T) {
 	)
 
 	t.Run("simple", func(t *testing.T) {
-		f := newMatchFilter("", "simple", nil)
+		f := testMatchFilter("", "simple", defaultModRatio)
 
 		tests := []test{
 			{"normal", args{25.0, 10.0}, 10.0},
@@ -135,13 +135,13 @@ func Test_matchFilter_AdjustWindow(t *testing.T) {
 	})
 
 	t.Run("calculated", func(t *testing.T) {
-		f := newMatchFilter("", "calculated", nil)
+		f := testMatchFilter("", "calculated", defaultModRatio)
 
 		tests := []test{
-			{"normal 1", args{25.0, 10.0}, 4.99963172016425},
-			{"normal 2", args{72.5, 23.0}, 13.298016797524529},
-			{"oob lower", args{-5.0, 10.0}, 0.24469939362609208},
-			{"oob upper", args{115.0, 10.0}, 2.0609219281580544},
+			{"normal 1", args{25.0, 10.0}, 4.999999999999998},
+			{"normal 2", args{72.5, 23.0}, 13.298996347962659},
+			{"oob lower", args{-5.0, 10.0}, 0.24471741852423184},
+			{"oob upper", args{115.0, 10.0}, 2.061073738537633},
 		}
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
@@ -151,3 +151,94 @@ func Test_matchFilter_AdjustWindow(t *testing.T) {
 		}
 	})
 }
+
+func testMatchFilter(scoreBoundFilter, matchingWindowFilter string, scoreModRatio []float64) *matchFilter {
+	f, err := newMatchFilter(&Config{
+		FilterParams:         DefaultFilterParams(),
+		ScoreBoundFilter:     scoreBoundFilter,
+		MatchingWindowFilter: matchingWindowFilter,
+		ScoreModRatio:        scoreModRatio,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+type constFilter struct {
+	score float64
+}
+
+func (f constFilter) AdjustPartyScore(int, float64) float64  { return f.score }
+func (f constFilter) BoundScore(float64) float64             { return f.score }
+func (f constFilter) ModifyScore(int, float64) float64       { return f.score }
+func (f constFilter) AdjustWindow(_, window float64) float64 { return window }
+
+func Test_NewFilter(t *testing.T) {
+	RegisterFilter("test-const", func(conf *Config) (Filter, error) {
+		return constFilter{score: conf.FilterParams.ScoreMid}, nil
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		f, err := NewFilter(&Config{Filter: "test-const", FilterParams: FilterParams{ScoreMid: 42.0}})
+		assert.NoError(t, err)
+		assert.Equal(t, 42.0, f.BoundScore(10.0))
+	})
+
+	t.Run("default", func(t *testing.T) {
+		f, err := NewFilter(DefaultConfig())
+		assert.NoError(t, err)
+		assert.IsType(t, &matchFilter{}, f)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := NewFilter(&Config{Filter: "unknown"})
+		assert.ErrorIs(t, err, ErrUnknownFilter)
+
+		_, err = NewFilter(&Config{ScoreBoundFilter: "curvy"})
+		assert.ErrorIs(t, err, ErrUnknownFilter)
+
+		_, err = NewFilter(&Config{MatchingWindowFilter: "calculate"})
+		assert.ErrorIs(t, err, ErrUnknownFilter)
+	})
+
+	t.Run("duplicated", func(t *testing.T) {
+		assert.Panics(t, func() { RegisterFilter(DefaultFilterName, func(*Config) (Filter, error) { return nil, nil }) })
+	})
+}
+
+func Test_matchFilter_params(t *testing.T) {
+	// score range of [0, 200] is mapped to the boundary of [0, 100]
+	fp := DefaultFilterParams()
+	fp.ScoreMid, fp.ScoreRange = 100.0, 100.0
+	f, err := newMatchFilter(&Config{FilterParams: fp, ScoreModRatio: defaultModRatio})
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, f.BoundScore(100.0))
+	assert.Equal(t, 75.0, f.BoundScore(150.0))
+	assert.Equal(t, 100.0, f.BoundScore(250.0))
+
+	// the calculated window is kept at the middle of the boundary whatever the curve is
+	fp.Slope = 5.0
+	f, err = newMatchFilter(&Config{FilterParams: fp, ScoreBoundFilter: "curve", MatchingWindowFilter: "calculated", ScoreModRatio: defaultModRatio})
+	assert.NoError(t, err)
+	assert.InDelta(t, 10.0, f.AdjustWindow(50.0, 10.0), 1e-9)
+	assert.Less(t, f.AdjustWindow(90.0, 10.0), 10.0)
+
+	// zeros are used as they are
+	fp = DefaultFilterParams()
+	fp.BoundMin, fp.BoundMax, fp.ScoreMid = 0.0, 100.0, 0.0
+	f, err = newMatchFilter(&Config{FilterParams: fp, ScoreModRatio: defaultModRatio})
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, f.BoundScore(0.0))
+
+	// the zero value is the default
+	f, err = newMatchFilter(&Config{ScoreModRatio: defaultModRatio})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultFilterParams(), f.params)
+
+	// invalid parameters
+	_, err = newMatchFilter(&Config{FilterParams: FilterParams{BoundMin: 1.0}, ScoreModRatio: defaultModRatio})
+	assert.Error(t, err)
+	_, err = newMatchFilter(&Config{FilterParams: DefaultFilterParams()})
+	assert.ErrorContains(t, err, "score_mod_ratio is empty")
+}
@@ -0,0 +1,82 @@
+package matchqueue
+
+import (
+	"maps"
+	"math"
+	"slices"
+)
+
+// latencyLimit returns the maximum latency (millisecond) allowed for a party which retried matching the given times.
+// It returns 0 if latencies of players are not considered.
+func (c *Config) latencyLimit(retry int) float64 {
+	if c.MaxLatency <= 0.0 {
+		return 0.0
+	}
+
+	limit := c.MaxLatency + float64(retry)*c.LatencyAdjustPerRetry
+	if c.LatencyLimit > 0.0 {
+		limit = min(limit, c.LatencyLimit)
+	}
+	return limit
+}
+
+// selectDatacenter returns the datacenter where all players of the parties can play,
+// which has the lowest latency of the slowest player.
+// Every player must have a latency to the datacenter within the limit of its party.
+// Players who do not report latencies can play in any datacenter.
+//
+// It returns an empty name and true if latencies are not considered or no player reports them,
+// and false if there is no datacenter for the parties.
+func (q *queue) selectDatacenter(parties ...*party) (string, bool) {
+	if q == nil || q.config.MaxLatency <= 0.0 {
+		return "", true
+	}
+
+	candidates := map[string]float64{}
+	reported := false
+	for _, p := range parties {
+		limit := q.config.latencyLimit(p.lastRetry)
+		for _, pl := range p.players {
+			if len(pl.Latency) == 0 {
+				continue
+			}
+
+			if !reported {
+				// datacenters reported by the first player
+				for dc, lat := range pl.Latency {
+					if lat <= limit {
+						candidates[dc] = lat
+					}
+				}
+				reported = true
+			} else {
+				for dc, worst := range candidates {
+					lat, ok := pl.Latency[dc]
+					if !ok || lat > limit {
+						delete(candidates, dc)
+					} else {
+						candidates[dc] = max(worst, lat)
+					}
+				}
+			}
+
+			if len(candidates) == 0 {
+				return "", false
+			}
+		}
+	}
+
+	best, bestLat := "", math.Inf(1)
+	for _, dc := range slices.Sorted(maps.Keys(candidates)) {
+		if candidates[dc] < bestLat {
+			best, bestLat = dc, candidates[dc]
+		}
+	}
+	return best, true
+}
+
+// hasDatacenter checks if players of the parties have a common datacenter.
+func (q *queue) hasDatacenter(parties ...*party) bool {
+	_, ok := q.selectDatacenter(parties...)
+	return ok
+}
@@ -0,0 +1,83 @@
+package matchqueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_selectDatacenter(t *testing.T) {
+	conf := DefaultConfig()
+	conf.MaxLatency = 50.0
+	conf.LatencyAdjustPerRetry = 20.0
+	conf.LatencyLimit = 80.0
+	q := New(conf).(*queue)
+
+	newTestParty := func(retry int, latencies ...map[string]float64) *party {
+		var players []*Player
+		for i, l := range latencies {
+			players = append(players, &Player{ID: PlayerID(i + 1), Score: 25.0, Latency: l})
+		}
+		p := newParty(q, players)
+		p.lastRetry = retry
+		return p
+	}
+
+	tests := []struct {
+		name    string
+		parties []*party
+		want    string
+		wantOk  bool
+	}{
+		{"not reported", []*party{newTestParty(0, nil), newTestParty(0, nil)}, "", true},
+		{"lowest", []*party{newTestParty(0, map[string]float64{"eu": 40, "us": 30}), newTestParty(0, map[string]float64{"eu": 20, "us": 45})}, "eu", true},
+		{"tie", []*party{newTestParty(0, map[string]float64{"us": 30, "eu": 30})}, "eu", true},
+		{"wildcard", []*party{newTestParty(0, map[string]float64{"eu": 70, "us": 30}), newTestParty(0, nil)}, "us", true},
+		{"too slow", []*party{newTestParty(0, map[string]float64{"eu": 30}), newTestParty(0, map[string]float64{"eu": 60})}, "", false},
+		{"relaxed", []*party{newTestParty(0, map[string]float64{"eu": 30}), newTestParty(1, map[string]float64{"eu": 60})}, "eu", true},
+		{"limit", []*party{newTestParty(5, map[string]float64{"eu": 90})}, "", false},
+		{"no common", []*party{newTestParty(0, map[string]float64{"eu": 30}), newTestParty(0, map[string]float64{"us": 30})}, "", false},
+		{"party member", []*party{newTestParty(0, map[string]float64{"eu": 30}, map[string]float64{"us": 30})}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := q.selectDatacenter(tt.parties...)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// latencies are not considered without max latency
+	q.config.MaxLatency = 0.0
+	got, ok := q.selectDatacenter(newTestParty(0, map[string]float64{"eu": 30}), newTestParty(0, map[string]float64{"us": 30}))
+	assert.True(t, ok)
+	assert.Empty(t, got)
+}
+
+func Test_queue_latency(t *testing.T) {
+	conf := DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.NumRoundToCreateGroup = 1
+	conf.InitMatchWindow = conf.MaxMatchWindow
+	conf.MaxLatency = 50.0
+	conf.LatencyAdjustPerRetry = 20.0
+	conf.LatencyLimit = 100.0
+
+	q := New(conf).(*queue)
+	require.NoError(t, q.AddPlayer([]*Player{{ID: 1, Score: 25.0, Latency: map[string]float64{"eu": 30, "us": 120}}}))
+	require.NoError(t, q.AddPlayer([]*Player{{ID: 2, Score: 25.0, Latency: map[string]float64{"eu": 90, "us": 40}}}))
+
+	// the max latency is relaxed to 70 in the second round and 90 in the third round
+	for round := 1; round <= 2; round++ {
+		groups, err := q.ProcMatching()
+		require.NoError(t, err)
+		assert.Empty(t, groups, "round %d", round)
+	}
+
+	groups, err := q.ProcMatching()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "eu", groups[0].Datacenter)
+}
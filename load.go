@@ -0,0 +1,141 @@
+package matchqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of environment variables which override configurations.
+const EnvPrefix = "MATCHQUEUE_"
+
+// LoadConfig reads the configuration from a JSON or YAML file.
+// Values in the file are merged over DefaultConfig, and then environment variables are applied.
+// See ApplyEnv for the environment variables.
+// The loaded configuration is validated.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := DefaultConfig()
+	if err := UnmarshalConfig(data, filepath.Ext(path), conf); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := ApplyEnv(conf, os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// UnmarshalConfig decodes data of the format into v. The format is a file extension, such as ".json" and ".yaml".
+// Fields which do not appear in data keep their values.
+func UnmarshalConfig(data []byte, format string, v any) error {
+	switch strings.ToLower(format) {
+	case ".json":
+		return json.Unmarshal(data, v)
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, v)
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownConfigFormat, format)
+}
+
+// ApplyEnv overrides the configuration with environment variables obtained by lookup.
+// Name of the variable is EnvPrefix followed by the upper-cased json name of the field,
+// such as MATCHQUEUE_INIT_MATCH_WINDOW and MATCHQUEUE_FILTER_PARAMS_SLOPE.
+// Slices of numbers are comma separated, and values starting with '[' or '{' are decoded as JSON.
+func ApplyEnv(conf *Config, lookup func(string) (string, bool)) error {
+	return applyEnv(reflect.ValueOf(conf).Elem(), EnvPrefix, lookup)
+}
+
+func applyEnv(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := prefix + strings.ToUpper(tag)
+		fv := v.Field(i)
+
+		s, ok := lookup(name)
+		if !ok {
+			if fv.Kind() == reflect.Struct {
+				if err := applyEnv(fv, name+"_", lookup); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := setEnvValue(fv, s); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setEnvValue(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{") {
+		return json.Unmarshal([]byte(s), v.Addr().Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var elems []string
+		if s != "" {
+			elems = strings.Split(s, ",")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, e := range elems {
+			if err := setEnvValue(slice.Index(i), e); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
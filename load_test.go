@@ -0,0 +1,73 @@
+package matchqueue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("json", func(t *testing.T) {
+		conf, err := LoadConfig(write("conf.json", `{"init_match_window": 12.5, "score_mod_ratio": [1.0, 0.5], "filter_params": {"slope": 2.0}}`))
+		require.NoError(t, err)
+
+		want := DefaultConfig()
+		want.InitMatchWindow = 12.5
+		want.ScoreModRatio = []float64{1.0, 0.5}
+		want.FilterParams.Slope = 2.0
+		assert.Equal(t, want, conf)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		conf, err := LoadConfig(write("conf.yaml", "min_num_to_create_group: 5\nmax_num_to_create_group: 10\nteams:\n  - {min_size: 1, max_size: 1}\n  - {min_size: 4, max_size: 4}\n"))
+		require.NoError(t, err)
+
+		want := DefaultConfig()
+		want.MinNumToCreateGroup = 5
+		want.MaxNumToCreateGroup = 10
+		want.Teams = []TeamLayout{{MinSize: 1, MaxSize: 1}, {MinSize: 4, MaxSize: 4}}
+		assert.Equal(t, want, conf)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("MATCHQUEUE_MIN_MATCH_WINDOW", "3")
+		t.Setenv("MATCHQUEUE_SCORE_MOD_RATIO", "1.0, 0.8,0.6")
+		t.Setenv("MATCHQUEUE_FILTER_PARAMS_SLOPE", "4.5")
+		t.Setenv("MATCHQUEUE_TEAMS", `[{"min_size": 2}, {"min_size": 2}]`)
+
+		conf, err := LoadConfig(write("env.json", `{"min_match_window": 4.0}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, 3.0, conf.MinMatchWindow)
+		assert.Equal(t, []float64{1.0, 0.8, 0.6}, conf.ScoreModRatio)
+		assert.Equal(t, 4.5, conf.FilterParams.Slope)
+		assert.Equal(t, []TeamLayout{{MinSize: 2}, {MinSize: 2}}, conf.Teams)
+	})
+
+	t.Run("invalid env", func(t *testing.T) {
+		t.Setenv("MATCHQUEUE_MAX_NUM_TO_CREATE_GROUP", "many")
+
+		_, err := LoadConfig(write("env.json", `{}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := LoadConfig(write("invalid.json", `{"min_num_to_create_group": 20}`))
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := LoadConfig(write("conf.toml", `init_match_window = 1.0`))
+		assert.ErrorIs(t, err, ErrUnknownConfigFormat)
+	})
+}
@@ -0,0 +1,436 @@
+package matchqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+const managerGroupBuffer = 256
+
+// QueueGroup is a group created by a queue of a manager.
+type QueueGroup struct {
+	Queue string // name of the queue
+	*Group
+}
+
+// ManagerState is the state of all queues of a manager.
+type ManagerState struct {
+	Queues map[string]State // state of each queue
+
+	// Total is the sum of states of all queues.
+	// Its averages, maxima and quantiles are calculated over all queues, and its Windows are
+	// merged by period. Round and MatchWindow are not aggregated and left zero.
+	Total State
+}
+
+// Manager manages multiple queues identified by names, such as "deathmatch/eu/ranked".
+//
+// A player can be in only one party of the manager at a time, but the party can be enrolled
+// in several queues at once. When a queue creates a group of the party, the party is withdrawn
+// from the other queues before any of them runs its next matching round.
+//
+// Like Runner, it is safe for concurrent use, and once started, it runs a matching round of
+// every queue on each interval and delivers the created groups through the channel returned by Groups.
+type Manager struct {
+	mu    sync.Mutex
+	opts  []Option
+	clock Clock
+
+	queues  map[string]*queue
+	names   []string                 // sorted names of queues; matching rounds run in this order
+	players map[PlayerID]*enrollment // enrollments of queued players
+
+	interval time.Duration
+	groups   chan QueueGroup
+
+	// loop control
+	status runnerStatus
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// enrollment is a party enrolled in queues of a manager.
+type enrollment struct {
+	leader  PlayerID
+	players []PlayerID
+	queues  []string // names of the queues in the order of enrollment
+}
+
+// NewManager creates a new manager.
+// The options are applied to every queue created by the manager.
+func NewManager(interval time.Duration, opts ...Option) *Manager {
+	return &Manager{
+		opts:     opts,
+		clock:    newOptions(opts).clock,
+		queues:   map[string]*queue{},
+		players:  map[PlayerID]*enrollment{},
+		interval: interval,
+		groups:   make(chan QueueGroup, managerGroupBuffer),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// AddQueue creates a queue of the name with the configuration.
+func (m *Manager) AddQueue(name string, conf *Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.queues[name]; ok {
+		return fmt.Errorf("%w: %q", ErrQueueExists, name)
+	}
+
+	filter, err := conf.validate()
+	if err != nil {
+		return fmt.Errorf("queue %q: %w", name, err)
+	}
+	q := newQueue(conf, m.opts)
+	if err := q.initWith(filter); err != nil {
+		return fmt.Errorf("queue %q: %w", name, err)
+	}
+
+	m.queues[name] = q
+	m.names = insertSortedSlice(m.names, name, func(i int) bool { return name < m.names[i] })
+	return nil
+}
+
+// RemoveQueue removes the queue of the name.
+// Parties in the queue are dropped without notifications,
+// but they stay enrolled in the other queues.
+func (m *Manager) RemoveQueue(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.queues[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownQueue, name)
+	}
+
+	for _, p := range q.parties {
+		if e := m.players[p.id]; e != nil {
+			e.queues = slices.DeleteFunc(e.queues, func(n string) bool { return n == name })
+			if len(e.queues) == 0 {
+				m.unindex(e)
+			}
+		}
+	}
+	delete(m.queues, name)
+	m.names = slices.DeleteFunc(m.names, func(n string) bool { return n == name })
+	return nil
+}
+
+// Queues returns the sorted names of the queues.
+func (m *Manager) Queues() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.names)
+}
+
+// AddPlayer adds a party to the queue of the name.
+// It returns *DuplicatePlayerError if any of the players is already queued in any queue of the manager.
+func (m *Manager) AddPlayer(name string, players []*Player) error {
+	return m.Enroll([]string{name}, players)
+}
+
+// Enroll adds a party to all the queues of the names at once.
+// It returns *DuplicatePlayerError if any of the players is already queued in any queue of the manager,
+// and ErrNoQueue if no name is given.
+func (m *Manager) Enroll(names []string, players []*Player) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(players) == 0 {
+		return nil
+	}
+	if len(names) == 0 {
+		return ErrNoQueue
+	}
+
+	var queues []string
+	for _, name := range names {
+		if _, ok := m.queues[name]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownQueue, name)
+		}
+		if !slices.Contains(queues, name) {
+			queues = append(queues, name)
+		}
+	}
+
+	e := &enrollment{leader: players[0].ID, queues: queues}
+	for _, pl := range players {
+		if queued, ok := m.players[pl.ID]; ok {
+			return &DuplicatePlayerError{Player: pl.ID, Leader: queued.leader}
+		}
+		if slices.Contains(e.players, pl.ID) {
+			return &DuplicatePlayerError{Player: pl.ID, Leader: e.leader}
+		}
+		e.players = append(e.players, pl.ID)
+	}
+
+	for i, name := range queues {
+		if err := m.queues[name].AddPlayer(players); err != nil {
+			// withdraw the party from the queues where it is added
+			for _, added := range queues[:i] {
+				m.queues[added].RemovePlayer(e.leader, false)
+			}
+			return fmt.Errorf("queue %q: %w", name, err)
+		}
+	}
+	for _, id := range e.players {
+		m.players[id] = e
+	}
+	return nil
+}
+
+// RemovePlayer removes the party of the player from all queues where it is enrolled.
+// If updateState is true, the party is counted as canceled only in the first queue where it is enrolled.
+func (m *Manager) RemovePlayer(id PlayerID, updateState bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.players[id]
+	if !ok {
+		return
+	}
+
+	m.unindex(e)
+	for i, name := range e.queues {
+		m.queues[name].RemovePlayer(e.leader, updateState && i == 0)
+	}
+}
+
+// Withdraw removes the party of the player from the queue of the name, keeping it in the other queues where it is enrolled.
+// If updateState is true, the party is counted as canceled in the queue.
+func (m *Manager) Withdraw(name string, id PlayerID, updateState bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.queues[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownQueue, name)
+	}
+	e, ok := m.players[id]
+	if !ok || !slices.Contains(e.queues, name) {
+		return fmt.Errorf("%w: player %d in queue %q", ErrPlayerNotQueued, id, name)
+	}
+
+	q.RemovePlayer(e.leader, updateState)
+	e.queues = slices.DeleteFunc(e.queues, func(n string) bool { return n == name })
+	if len(e.queues) == 0 {
+		m.unindex(e)
+	}
+	return nil
+}
+
+// QueuesOf returns the names of the queues where the player is queued.
+func (m *Manager) QueuesOf(id PlayerID) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.players[id]; ok {
+		return slices.Clone(e.queues)
+	}
+	return nil
+}
+
+// QueueState returns the state of the queue of the name.
+func (m *Manager) QueueState(name string) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.queues[name]
+	if !ok {
+		return State{}, fmt.Errorf("%w: %q", ErrUnknownQueue, name)
+	}
+	return q.State(), nil
+}
+
+// State returns the state of each queue and the aggregated state of all queues.
+func (m *Manager) State() ManagerState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	ms := ManagerState{Queues: make(map[string]State, len(m.queues))}
+
+	total := &ms.Total
+	waits := waitSketch{}
+	var (
+		periods      []uint64
+		windows      = map[uint64]WindowState{}
+		windowsWaits = map[uint64]waitSketch{}
+	)
+
+	// a party enrolled in several queues is counted once
+	total.PlayerQueued = len(m.players)
+
+	for _, name := range m.names {
+		q := m.queues[name]
+		s := q.State()
+		ms.Queues[name] = s
+
+		total.GroupCreated += s.GroupCreated
+		total.WaitTimeAll += s.WaitTimeAll
+		total.WaitTimeMax = max(total.WaitTimeMax, s.WaitTimeMax)
+		total.PlayerMatched += s.PlayerMatched
+		total.CanceledWaitTimeAll += s.CanceledWaitTimeAll
+		total.CanceledWaitTimeMax = max(total.CanceledWaitTimeMax, s.CanceledWaitTimeMax)
+		total.PlayerCanceled += s.PlayerCanceled
+
+		waits.merge(q.stats.waits)
+		for _, w := range q.stats.windows {
+			ws, ww := w.collect(now)
+			sum, ok := windows[ws.Period]
+			if !ok {
+				periods = append(periods, ws.Period)
+				sum.Period = ws.Period
+				windowsWaits[ws.Period] = waitSketch{}
+			}
+			sum.GroupCreated += ws.GroupCreated
+			sum.PlayerMatched += ws.PlayerMatched
+			sum.PlayerCanceled += ws.PlayerCanceled
+			windows[ws.Period] = sum
+			windowsWaits[ws.Period].merge(ww)
+		}
+	}
+
+	if total.PlayerMatched > 0 {
+		total.WaitTimeAvg = total.WaitTimeAll / uint64(total.PlayerMatched)
+	}
+	if total.PlayerCanceled > 0 {
+		total.CanceledWaitTimeAvg = total.CanceledWaitTimeAll / uint64(total.PlayerCanceled)
+	}
+	p := waits.quantiles(0.5, 0.9, 0.99)
+	total.WaitTimeP50, total.WaitTimeP90, total.WaitTimeP99 = p[0], p[1], p[2]
+
+	slices.Sort(periods)
+	for _, period := range periods {
+		total.Windows = append(total.Windows, windows[period].finish(windowsWaits[period]))
+	}
+
+	return ms
+}
+
+// ProcMatching does a matching process of every queue in the order of names.
+// Created groups are returned to the caller and not delivered to Groups.
+func (m *Manager) ProcMatching() ([]QueueGroup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var (
+		created []QueueGroup
+		errs    []error
+	)
+	for _, name := range m.names {
+		groups, err := m.queues[name].ProcMatching()
+		if err != nil && !errors.Is(err, ErrNotEnoughPlayer) {
+			errs = append(errs, fmt.Errorf("queue %q: %w", name, err))
+		}
+
+		for _, g := range groups {
+			// withdraw matched parties from the other queues
+			for _, gp := range g.Parties {
+				e, ok := m.players[gp.Leader]
+				if !ok {
+					continue
+				}
+				m.unindex(e)
+				for _, other := range e.queues {
+					if other != name {
+						m.queues[other].RemovePlayer(gp.Leader, false)
+					}
+				}
+			}
+			created = append(created, QueueGroup{Queue: name, Group: g})
+		}
+	}
+
+	return created, errors.Join(errs...)
+}
+
+// now returns the current time of the queues' clock.
+func (m *Manager) now() time.Time {
+	if m.clock == nil {
+		return time.Now()
+	}
+	return m.clock.Now()
+}
+
+// unindex removes players of the enrollment from the index of queued players.
+func (m *Manager) unindex(e *enrollment) {
+	for _, id := range e.players {
+		delete(m.players, id)
+	}
+}
+
+// Groups returns the channel which delivers groups created by the matching loop.
+// The channel is closed when the manager stops.
+func (m *Manager) Groups() <-chan QueueGroup {
+	return m.groups
+}
+
+// Start starts the matching loop.
+// A manager can be started only once.
+func (m *Manager) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status != runnerIdle {
+		return ErrRunnerStarted
+	}
+	m.status = runnerRunning
+
+	go m.loop()
+	return nil
+}
+
+// Stop stops the matching loop and waits until it exits or ctx is done.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	if m.status != runnerRunning {
+		m.mu.Unlock()
+		return ErrRunnerNotRunning
+	}
+	m.status = runnerStopped
+	close(m.stop)
+	m.mu.Unlock()
+
+	select {
+	case <-m.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Manager) loop() {
+	defer close(m.done)
+	defer close(m.groups)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+
+		// groups are delivered even if some queues fail
+		groups, _ := m.ProcMatching()
+
+		for _, g := range groups {
+			select {
+			case m.groups <- g:
+			case <-m.stop:
+				return
+			}
+		}
+	}
+}
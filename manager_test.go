@@ -0,0 +1,223 @@
+package matchqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T, clock Clock) *Manager {
+	t.Helper()
+
+	duel := DefaultConfig()
+	duel.MinNumToCreateGroup = 2
+	duel.MaxNumToCreateGroup = 2
+	duel.NumRoundToCreateGroup = 1
+
+	m := NewManager(time.Millisecond, WithClock(clock))
+	require.NoError(t, m.AddQueue("duel/eu", duel))
+	require.NoError(t, m.AddQueue("duel/us", duel))
+	require.NoError(t, m.AddQueue("team/eu", DefaultConfig()))
+	return m
+}
+
+func Test_Manager_Queues(t *testing.T) {
+	m := newTestManager(t, NewFakeClock(time.Unix(0, 0)))
+
+	assert.ErrorIs(t, m.AddQueue("duel/eu", DefaultConfig()), ErrQueueExists)
+
+	invalid := DefaultConfig()
+	invalid.MinNumToCreateGroup = invalid.MaxNumToCreateGroup + 1
+	assert.ErrorIs(t, m.AddQueue("invalid", invalid), ErrInvalidConfig)
+
+	assert.Equal(t, []string{"duel/eu", "duel/us", "team/eu"}, m.Queues())
+
+	require.NoError(t, m.AddPlayer("team/eu", []*Player{{ID: 1, Score: 25.0}}))
+	require.NoError(t, m.RemoveQueue("team/eu"))
+	assert.ErrorIs(t, m.RemoveQueue("team/eu"), ErrUnknownQueue)
+	assert.Equal(t, []string{"duel/eu", "duel/us"}, m.Queues())
+
+	// players of the removed queue can be queued again
+	assert.Empty(t, m.QueuesOf(1))
+	assert.NoError(t, m.AddPlayer("duel/eu", []*Player{{ID: 1, Score: 25.0}}))
+}
+
+func Test_Manager_Players(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	m := newTestManager(t, clock)
+
+	assert.ErrorIs(t, m.AddPlayer("unknown", []*Player{{ID: 1}}), ErrUnknownQueue)
+
+	require.NoError(t, m.AddPlayer("team/eu", []*Player{{ID: 1, Score: 25.0}, {ID: 2, Score: 25.0}}))
+
+	// a player is queued in only one queue
+	assert.ErrorIs(t, m.AddPlayer("duel/eu", []*Player{{ID: 2, Score: 25.0}}), ErrPlayerQueued)
+	assert.ErrorIs(t, m.AddPlayer("team/eu", []*Player{{ID: 3, Score: 25.0}, {ID: 1, Score: 25.0}}), ErrPlayerQueued)
+
+	assert.Equal(t, []string{"team/eu"}, m.QueuesOf(2))
+
+	var dup *DuplicatePlayerError
+	require.ErrorAs(t, m.AddPlayer("duel/eu", []*Player{{ID: 3}, {ID: 3}}), &dup)
+	assert.Equal(t, DuplicatePlayerError{Player: 3, Leader: 3}, *dup)
+	require.ErrorAs(t, m.AddPlayer("duel/eu", []*Player{{ID: 2}}), &dup)
+	assert.Equal(t, DuplicatePlayerError{Player: 2, Leader: 1}, *dup)
+	assert.Empty(t, m.QueuesOf(3))
+
+	// removing by a member removes the whole party
+	clock.Advance(10 * time.Second)
+	m.RemovePlayer(2, true)
+	assert.Empty(t, m.QueuesOf(1))
+
+	state, err := m.QueueState("team/eu")
+	require.NoError(t, err)
+	assert.Equal(t, 2, state.PlayerCanceled)
+	assert.EqualValues(t, 10, state.CanceledWaitTimeMax)
+
+	_, err = m.QueueState("unknown")
+	assert.ErrorIs(t, err, ErrUnknownQueue)
+
+	// matched players can be queued again
+	require.NoError(t, m.AddPlayer("duel/eu", []*Player{{ID: 1, Score: 25.0}}))
+	require.NoError(t, m.AddPlayer("duel/eu", []*Player{{ID: 2, Score: 25.0}}))
+	groups, err := m.ProcMatching()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "duel/eu", groups[0].Queue)
+
+	assert.NoError(t, m.AddPlayer("duel/us", []*Player{{ID: 1, Score: 25.0}}))
+}
+
+func Test_Manager_Enroll(t *testing.T) {
+	m := newTestManager(t, NewFakeClock(time.Unix(0, 0)))
+
+	removed := map[string][]bool{}
+	for _, name := range m.Queues() {
+		m.queues[name].Subscribe(func(noti Notification) {
+			if noti.Message == NotifyPartyRemoved && noti.Data["leader"] == PlayerID(1) {
+				removed[name] = append(removed[name], noti.Data["canceled"].(bool))
+			}
+		})
+	}
+
+	assert.ErrorIs(t, m.Enroll([]string{"duel/eu", "unknown"}, []*Player{{ID: 1}}), ErrUnknownQueue)
+	assert.ErrorIs(t, m.Enroll(nil, []*Player{{ID: 1}}), ErrNoQueue)
+	assert.Empty(t, m.QueuesOf(1))
+
+	require.NoError(t, m.Enroll([]string{"team/eu", "duel/us", "duel/eu", "duel/us"}, []*Player{{ID: 1, Score: 25.0}}))
+	assert.Equal(t, []string{"team/eu", "duel/us", "duel/eu"}, m.QueuesOf(1))
+	assert.ErrorIs(t, m.Enroll([]string{"duel/eu"}, []*Player{{ID: 1, Score: 25.0}}), ErrPlayerQueued)
+
+	require.NoError(t, m.AddPlayer("duel/eu", []*Player{{ID: 2, Score: 25.0}}))
+	require.NoError(t, m.AddPlayer("duel/us", []*Player{{ID: 3, Score: 25.0}}))
+
+	// "duel/eu" runs first and takes the party, so "duel/us" cannot match it
+	groups, err := m.ProcMatching()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "duel/eu", groups[0].Queue)
+	assert.ElementsMatch(t, []PlayerID{1, 2}, []PlayerID{groups[0].Parties[0].Leader, groups[0].Parties[1].Leader})
+
+	assert.Empty(t, m.QueuesOf(1))
+	assert.Equal(t, []string{"duel/us"}, m.QueuesOf(3))
+	assert.Equal(t, map[string][]bool{"team/eu": {false}, "duel/us": {false}}, removed)
+
+	state := m.State()
+	assert.Equal(t, 1, state.Queues["duel/us"].PlayerQueued)
+	assert.Equal(t, 0, state.Queues["team/eu"].PlayerQueued)
+	assert.Equal(t, 1, state.Total.PlayerQueued)
+
+	// a party enrolled in several queues is counted once
+	require.NoError(t, m.Enroll([]string{"team/eu", "duel/eu"}, []*Player{{ID: 1, Score: 25.0}, {ID: 4, Score: 25.0}}))
+	assert.Equal(t, 3, m.State().Total.PlayerQueued)
+
+	// removing a party withdraws it from all queues, and it is canceled only in the first queue
+	m.RemovePlayer(1, true)
+	assert.Empty(t, m.QueuesOf(4))
+	assert.Equal(t, map[string][]bool{"team/eu": {false, true}, "duel/us": {false}, "duel/eu": {false}}, removed)
+	assert.Equal(t, 2, m.State().Total.PlayerCanceled)
+
+	// withdrawing a party keeps it in the other queues
+	require.NoError(t, m.Enroll([]string{"team/eu", "duel/eu"}, []*Player{{ID: 6, Score: 25.0}}))
+	assert.ErrorIs(t, m.Withdraw("duel/us", 6, true), ErrPlayerNotQueued)
+	assert.ErrorIs(t, m.Withdraw("unknown", 6, true), ErrUnknownQueue)
+	require.NoError(t, m.Withdraw("duel/eu", 6, true))
+	assert.Equal(t, []string{"team/eu"}, m.QueuesOf(6))
+	assert.Equal(t, 1, m.State().Queues["duel/eu"].PlayerCanceled)
+	require.NoError(t, m.Withdraw("team/eu", 6, false))
+	assert.Empty(t, m.QueuesOf(6))
+	assert.ErrorIs(t, m.Withdraw("team/eu", 6, false), ErrPlayerNotQueued)
+
+	// a party stays enrolled in the other queues when a queue is removed
+	require.NoError(t, m.Enroll([]string{"team/eu", "duel/eu"}, []*Player{{ID: 5, Score: 25.0}}))
+	require.NoError(t, m.RemoveQueue("duel/eu"))
+	assert.Equal(t, []string{"team/eu"}, m.QueuesOf(5))
+	require.NoError(t, m.RemoveQueue("team/eu"))
+	assert.Empty(t, m.QueuesOf(5))
+}
+
+func Test_Manager_State(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1000, 0))
+	m := newTestManager(t, clock)
+
+	for i, name := range []string{"duel/eu", "duel/us", "duel/us"} {
+		require.NoError(t, m.AddPlayer(name, []*Player{{ID: PlayerID(i*2 + 1), Score: 25.0}}))
+		require.NoError(t, m.AddPlayer(name, []*Player{{ID: PlayerID(i*2 + 2), Score: 25.0}}))
+	}
+	require.NoError(t, m.AddPlayer("team/eu", []*Player{{ID: 100, Score: 25.0}}))
+
+	clock.Advance(20 * time.Second)
+	groups, err := m.ProcMatching()
+	require.NoError(t, err)
+	assert.Len(t, groups, 3)
+
+	ms := m.State()
+	require.Len(t, ms.Queues, 3)
+	assert.Equal(t, 1, ms.Queues["duel/eu"].GroupCreated)
+	assert.Equal(t, 2, ms.Queues["duel/us"].GroupCreated)
+
+	assert.Equal(t, 1, ms.Total.PlayerQueued)
+	assert.Equal(t, 3, ms.Total.GroupCreated)
+	assert.Equal(t, 6, ms.Total.PlayerMatched)
+	assert.EqualValues(t, 20, ms.Total.WaitTimeAvg)
+	assert.InDelta(t, 20, ms.Total.WaitTimeP50, 1)
+
+	require.Len(t, ms.Total.Windows, len(DefaultConfig().StatWindowSec))
+	assert.EqualValues(t, 60, ms.Total.Windows[0].Period)
+	assert.Equal(t, 3, ms.Total.Windows[0].GroupCreated)
+	assert.Equal(t, 1.0, ms.Total.Windows[0].MatchRate)
+}
+
+func Test_Manager_Loop(t *testing.T) {
+	m := newTestManager(t, nil)
+
+	for i := 1; i <= 10; i++ {
+		name := "duel/eu"
+		if i%2 == 0 {
+			name = "duel/us"
+		}
+		require.NoError(t, m.AddPlayer(name, []*Player{{ID: PlayerID(i), Score: 25.0}}))
+	}
+
+	require.NoError(t, m.Start())
+	assert.ErrorIs(t, m.Start(), ErrRunnerStarted)
+
+	created := map[string]int{}
+	timeout := time.After(5 * time.Second)
+	for created["duel/eu"]+created["duel/us"] < 4 {
+		select {
+		case g := <-m.Groups():
+			created[g.Queue]++
+		case <-timeout:
+			t.Fatalf("only %v groups created", created)
+		}
+	}
+
+	require.NoError(t, m.Stop(context.Background()))
+	assert.ErrorIs(t, m.Stop(context.Background()), ErrRunnerNotRunning)
+
+	_, ok := <-m.Groups()
+	assert.False(t, ok)
+}
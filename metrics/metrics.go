@@ -0,0 +1,279 @@
+// Package metrics exports states of matching queues in the Prometheus text exposition format.
+//
+// Metrics of a queue are labeled with the name of the queue:
+//
+//	matchqueue_rounds_total                   counter   number of matching rounds
+//	matchqueue_players_queued                 gauge     number of players in the queue when the latest round started
+//	matchqueue_match_window                   gauge     match window when the latest round started
+//	matchqueue_groups_created_total           counter   number of created groups
+//	matchqueue_players_matched_total          counter   number of matched players
+//	matchqueue_players_canceled_total         counter   number of canceled players
+//	matchqueue_matched_wait_seconds           histogram wait time of matched players
+//	matchqueue_canceled_wait_seconds          histogram wait time of canceled players
+//
+// Counters and gauges come from the state of the queue, so they cover the whole lifetime of the queue.
+// Histograms are recorded from notifications of the queue and cover only the time since it is registered;
+// their counts are less than the counters if the queue has already matched or canceled players.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scalcor/matchqueue"
+)
+
+// DefaultBuckets are upper bounds (second) of wait time histograms.
+var DefaultBuckets = []float64{1, 2, 5, 10, 20, 30, 60, 120, 300, 600}
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Exporter collects metrics of queues and serves them over HTTP.
+type Exporter struct {
+	mu      sync.Mutex
+	buckets []float64
+	queues  map[string]*queueMetrics
+}
+
+var _ http.Handler = new(Exporter)
+
+// NewExporter creates an exporter whose histograms have the given buckets.
+// If buckets is empty, DefaultBuckets is used.
+func NewExporter(buckets ...float64) *Exporter {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return &Exporter{buckets: buckets, queues: map[string]*queueMetrics{}}
+}
+
+// Register starts collecting metrics of the queue by the name.
+// Histograms of the queue record players matched or canceled after the registration.
+// If the queue is used by multiple goroutines, register the matchqueue.Runner wrapping it.
+// The returned function stops collecting and removes the queue's metrics.
+func (e *Exporter) Register(name string, q matchqueue.Queue) (unregister func()) {
+	m := &queueMetrics{
+		q:        q,
+		matched:  newHistogram(e.buckets),
+		canceled: newHistogram(e.buckets),
+	}
+
+	// subscribe before publishing, since a later registration of the name unsubscribes m
+	m.unsubscribe = q.Subscribe(m.handle)
+
+	e.mu.Lock()
+	if old, ok := e.queues[name]; ok {
+		old.unsubscribe()
+	}
+	e.queues[name] = m
+	e.mu.Unlock()
+
+	return func() {
+		e.mu.Lock()
+		if e.queues[name] == m {
+			delete(e.queues, name)
+		}
+		e.mu.Unlock()
+
+		m.unsubscribe()
+	}
+}
+
+// ServeHTTP writes metrics of all registered queues.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	e.WriteTo(w)
+}
+
+// WriteTo writes metrics of all registered queues in the Prometheus text exposition format.
+func (e *Exporter) WriteTo(w io.Writer) (int64, error) {
+	e.mu.Lock()
+	names := make([]string, 0, len(e.queues))
+	for name := range e.queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	queues := make([]*queueMetrics, len(names))
+	for i, name := range names {
+		queues[i] = e.queues[name]
+	}
+	e.mu.Unlock()
+
+	// take snapshots without holding the exporter's lock,
+	// since the queue may be notifying its metrics at the same time.
+	snapshots := make([]queueSnapshot, len(queues))
+	for i, m := range queues {
+		snapshots[i] = m.snapshot(names[i])
+	}
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+
+	writeFamily(cw, "matchqueue_rounds_total", "counter", "Number of matching rounds.", snapshots,
+		func(s queueSnapshot) float64 { return float64(s.state.Round) })
+	writeFamily(cw, "matchqueue_players_queued", "gauge", "Number of players in the queue when the latest round started.", snapshots,
+		func(s queueSnapshot) float64 { return float64(s.state.PlayerQueued) })
+	writeFamily(cw, "matchqueue_match_window", "gauge", "Match window when the latest round started.", snapshots,
+		func(s queueSnapshot) float64 { return s.state.MatchWindow })
+	writeFamily(cw, "matchqueue_groups_created_total", "counter", "Number of created groups.", snapshots,
+		func(s queueSnapshot) float64 { return float64(s.state.GroupCreated) })
+	writeFamily(cw, "matchqueue_players_matched_total", "counter", "Number of matched players.", snapshots,
+		func(s queueSnapshot) float64 { return float64(s.state.PlayerMatched) })
+	writeFamily(cw, "matchqueue_players_canceled_total", "counter", "Number of canceled players.", snapshots,
+		func(s queueSnapshot) float64 { return float64(s.state.PlayerCanceled) })
+	writeHistogramFamily(cw, "matchqueue_matched_wait_seconds", "Wait time of players matched since the queue was registered.", snapshots,
+		func(s queueSnapshot) histogram { return s.matched })
+	writeHistogramFamily(cw, "matchqueue_canceled_wait_seconds", "Wait time of players canceled since the queue was registered.", snapshots,
+		func(s queueSnapshot) histogram { return s.canceled })
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// queueMetrics holds metrics of a queue.
+type queueMetrics struct {
+	q           matchqueue.Queue
+	unsubscribe func()
+
+	mu       sync.Mutex
+	matched  histogram
+	canceled histogram
+}
+
+type queueSnapshot struct {
+	name     string
+	state    matchqueue.State
+	matched  histogram
+	canceled histogram
+}
+
+// handle observes notifications of the queue.
+func (m *queueMetrics) handle(n matchqueue.Notification) {
+	switch n.Message {
+	case matchqueue.NotifyGroupCreated:
+		g, ok := n.Data["group"].(*matchqueue.Group)
+		if !ok {
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for _, gp := range g.Parties {
+			m.matched.observe(gp.WaitTime.Seconds(), len(gp.Players))
+		}
+
+	case matchqueue.NotifyPartyRemoved:
+		canceled, _ := n.Data["canceled"].(bool)
+		waitTime, _ := n.Data["wait_time"].(time.Duration)
+		players, _ := n.Data["players"].([]matchqueue.PlayerID)
+		if !canceled {
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.canceled.observe(waitTime.Seconds(), len(players))
+	}
+}
+
+func (m *queueMetrics) snapshot(name string) queueSnapshot {
+	// the queue's state must be taken before locking metrics,
+	// since the queue notifies metrics while it is locked.
+	state := m.q.State()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return queueSnapshot{name: name, state: state, matched: m.matched.clone(), canceled: m.canceled.clone()}
+}
+
+// histogram counts observed values by buckets.
+type histogram struct {
+	bounds []float64 // upper bounds of buckets; shared by histograms of an exporter
+	counts []uint64  // count of each bucket, not cumulative; the last one is for +Inf
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) histogram {
+	return histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+// observe adds the value cnt times.
+func (h *histogram) observe(v float64, cnt int) {
+	if cnt <= 0 {
+		return
+	}
+
+	i := sort.SearchFloat64s(h.bounds, v)
+	h.counts[i] += uint64(cnt)
+	h.sum += v * float64(cnt)
+	h.count += uint64(cnt)
+}
+
+func (h histogram) clone() histogram {
+	h.counts = slices.Clone(h.counts)
+	return h
+}
+
+func writeFamily(w *countWriter, name, typ, help string, snapshots []queueSnapshot, value func(queueSnapshot) float64) {
+	w.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	for _, s := range snapshots {
+		w.printf("%s{queue=\"%s\"} %s\n", name, escapeLabel(s.name), formatFloat(value(s)))
+	}
+}
+
+func writeHistogramFamily(w *countWriter, name, help string, snapshots []queueSnapshot, value func(queueSnapshot) histogram) {
+	w.printf("# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, s := range snapshots {
+		h := value(s)
+		label := escapeLabel(s.name)
+
+		cumulative := uint64(0)
+		for i, bound := range h.bounds {
+			cumulative += h.counts[i]
+			w.printf("%s_bucket{queue=\"%s\",le=\"%s\"} %d\n", name, label, formatFloat(bound), cumulative)
+		}
+		w.printf("%s_bucket{queue=\"%s\",le=\"+Inf\"} %d\n", name, label, h.count)
+		w.printf("%s_sum{queue=\"%s\"} %s\n", name, label, formatFloat(h.sum))
+		w.printf("%s_count{queue=\"%s\"} %d\n", name, label, h.count)
+	}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// countWriter counts written bytes and keeps the first error.
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (w *countWriter) printf(format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scalcor/matchqueue"
+)
+
+func TestExporter(t *testing.T) {
+	clock := matchqueue.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	conf := matchqueue.DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.NumRoundToCreateGroup = 1
+	q := matchqueue.New(conf, matchqueue.WithClock(clock))
+
+	e := NewExporter(5, 10)
+	unregister := e.Register(`duel "eu"`, q)
+
+	q.AddPlayer([]*matchqueue.Player{{ID: 1, Score: 25.0}})
+	q.AddPlayer([]*matchqueue.Player{{ID: 2, Score: 25.0}})
+	q.AddPlayer([]*matchqueue.Player{{ID: 3, Score: 25.0}, {ID: 4, Score: 25.0}})
+	clock.Advance(3 * time.Second)
+	q.RemovePlayer(3, true)
+	clock.Advance(4 * time.Second)
+	_, err := q.ProcMatching()
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, contentType, rec.Header().Get("Content-Type"))
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		`# TYPE matchqueue_rounds_total counter`,
+		`matchqueue_rounds_total{queue="duel \"eu\""} 1`,
+		`matchqueue_players_queued{queue="duel \"eu\""} 2`,
+		`matchqueue_match_window{queue="duel \"eu\""} 10`,
+		`matchqueue_groups_created_total{queue="duel \"eu\""} 1`,
+		`matchqueue_players_matched_total{queue="duel \"eu\""} 2`,
+		`matchqueue_players_canceled_total{queue="duel \"eu\""} 2`,
+		`# TYPE matchqueue_matched_wait_seconds histogram`,
+		`matchqueue_matched_wait_seconds_bucket{queue="duel \"eu\"",le="5"} 0`,
+		`matchqueue_matched_wait_seconds_bucket{queue="duel \"eu\"",le="10"} 2`,
+		`matchqueue_matched_wait_seconds_bucket{queue="duel \"eu\"",le="+Inf"} 2`,
+		`matchqueue_matched_wait_seconds_sum{queue="duel \"eu\""} 14`,
+		`matchqueue_matched_wait_seconds_count{queue="duel \"eu\""} 2`,
+		`matchqueue_canceled_wait_seconds_bucket{queue="duel \"eu\"",le="5"} 2`,
+		`matchqueue_canceled_wait_seconds_sum{queue="duel \"eu\""} 6`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+
+	unregister()
+
+	var sb strings.Builder
+	_, err = e.WriteTo(&sb)
+	require.NoError(t, err)
+	assert.NotContains(t, sb.String(), "duel")
+}
+
+func TestExporter_registeredLater(t *testing.T) {
+	conf := matchqueue.DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.NumRoundToCreateGroup = 1
+	q := matchqueue.New(conf)
+
+	q.AddPlayer([]*matchqueue.Player{{ID: 1, Score: 25.0}})
+	q.AddPlayer([]*matchqueue.Player{{ID: 2, Score: 25.0}})
+	_, err := q.ProcMatching()
+	require.NoError(t, err)
+
+	// counters cover the lifetime of the queue, and histograms cover the time since the registration
+	e := NewExporter()
+	e.Register("duel", q)
+
+	var sb strings.Builder
+	_, err = e.WriteTo(&sb)
+	require.NoError(t, err)
+	assert.Contains(t, sb.String(), `matchqueue_players_matched_total{queue="duel"} 2`)
+	assert.Contains(t, sb.String(), `matchqueue_matched_wait_seconds_count{queue="duel"} 0`)
+}
+
+func TestExporter_registerConcurrently(t *testing.T) {
+	e := NewExporter()
+	r := matchqueue.NewRunner(matchqueue.New(matchqueue.DefaultConfig()), time.Second)
+
+	// registrations of the same name replace each other
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.Register("deathmatch", r)
+		}()
+	}
+	wg.Wait()
+
+	var b strings.Builder
+	_, err := e.WriteTo(&b)
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `queue="deathmatch"`)
+}
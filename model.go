@@ -1,20 +1,81 @@
 package matchqueue
 
+import "time"
+
 type (
 	PlayerID uint64
 	Player   struct {
-		ID    PlayerID
-		Score float64
+		ID     PlayerID `json:"id"`
+		Score  float64  `json:"score"`
+		Rating *Rating  `json:"rating,omitempty"` // if given, its mean is used as the score
+		Roles  []string `json:"roles,omitempty"`  // roles the player can take; empty for any role
+
+		// attributes used by constraints; a value is a string, a number or a set of strings
+		Attributes map[string]any `json:"attributes,omitempty"`
+
+		// latency (millisecond) to each datacenter; empty if the player can play in any datacenter
+		Latency map[string]float64 `json:"latency,omitempty"`
+	}
+
+	// Rating is a skill rating of a player with its uncertainty, as in Glicko or TrueSkill.
+	// It is in the same scale as Score; the filter of the queue must be configured for the scale,
+	// such as FilterParams.ScoreMid and ScoreRange of 1500 for Elo or Glicko ratings.
+	Rating struct {
+		Mean      float64 `json:"mean"`
+		Deviation float64 `json:"deviation"` // standard deviation of the rating
 	}
 
 	GroupID uint64
 	Group   struct {
 		ID           GroupID
-		Players      [2][]*Player
+		Players      [][]*Player // players of each team
 		CreatedRound uint64
+		CreatedAt    time.Time
+		Parties      []GroupParty        // matched parties
+		Roles        map[PlayerID]string // assigned role of each player who takes a role; nil if roles are not configured
+		Datacenter   string              // datacenter selected for the group; empty if latencies are not considered
+
+		// match quality
+		ScoreSpread    float64   // difference between the highest and the lowest score of players
+		MatchWindow    float64   // match window of the party which the group is formed around
+		TeamScores     []float64 // average score of each team
+		WinProbability []float64 // predicted probability that each team wins
+	}
+
+	// PartyInfo is information of a party in a queue.
+	PartyInfo struct {
+		Leader   PlayerID
+		Players  []PlayerID
+		WaitTime time.Duration
+		Score    float64 // score of the party used for matching
+		Window   float64 // match window of the party
+	}
+
+	// GroupParty is a party matched into a group.
+	GroupParty struct {
+		Leader   PlayerID
+		Players  []PlayerID
+		Team     int // index of the team in Group.Players
+		WaitTime time.Duration
 	}
 )
 
+// skill returns the score of the player used for matching.
+func (pl *Player) skill() float64 {
+	if pl.Rating != nil {
+		return pl.Rating.Mean
+	}
+	return pl.Score
+}
+
+// deviation returns the uncertainty of the player's rating; 0 if the player has no rating.
+func (pl *Player) deviation() float64 {
+	if pl.Rating == nil {
+		return 0.0
+	}
+	return max(pl.Rating.Deviation, 0.0)
+}
+
 func (id PlayerID) IsValid() bool {
 	return id > 0
 }
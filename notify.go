@@ -0,0 +1,63 @@
+package matchqueue
+
+// Messages of notifications published by the queue.
+//
+// Data of each notification has the keys below:
+//
+//	party_enqueued:       leader, players, score, window
+//	party_removed:        leader, players, canceled, wait_time
+//	group_created:        group
+//	match_window_changed: old, new
+//	round_started:        round, queued, window
+//	round_finished:       round, created, queued, window
+//	party_window_widened: leader, old, new
+const (
+	NotifyPartyEnqueued      = "party_enqueued"
+	NotifyPartyRemoved       = "party_removed"
+	NotifyGroupCreated       = "group_created"
+	NotifyMatchWindowChanged = "match_window_changed"
+	NotifyRoundStarted       = "round_started"
+	NotifyRoundFinished      = "round_finished"
+	NotifyPartyWindowWidened = "party_window_widened"
+)
+
+type subscriber struct {
+	id int
+	fn func(Notification)
+}
+
+// notifier delivers notifications to its subscribers in the order of subscription.
+type notifier struct {
+	subscribers []subscriber
+	seq         int
+}
+
+func (n *notifier) Subscribe(fn func(Notification)) func() {
+	if fn == nil {
+		return func() {}
+	}
+
+	n.seq++
+	id := n.seq
+	n.subscribers = append(n.subscribers, subscriber{id: id, fn: fn})
+
+	return func() {
+		for i, s := range n.subscribers {
+			if s.id == id {
+				n.subscribers = append(n.subscribers[:i:i], n.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (n *notifier) notify(msg string, data map[string]any) {
+	if n == nil || len(n.subscribers) == 0 {
+		return
+	}
+
+	noti := Notification{Message: msg, Data: data}
+	for _, s := range n.subscribers {
+		s.fn(noti)
+	}
+}
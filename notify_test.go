@@ -0,0 +1,54 @@
+package matchqueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_notifier_Subscribe(t *testing.T) {
+	n := &notifier{}
+
+	var got []string
+	unsub1 := n.Subscribe(func(noti Notification) { got = append(got, "1:"+noti.Message) })
+	n.Subscribe(func(noti Notification) { got = append(got, "2:"+noti.Message) })
+
+	n.notify("a", nil)
+	unsub1()
+	n.notify("b", nil)
+
+	assert.Equal(t, []string{"1:a", "2:a", "2:b"}, got)
+}
+
+func Test_queue_Subscribe(t *testing.T) {
+	conf := DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.NumRoundToCreateGroup = 1
+
+	q := New(conf)
+
+	var got []string
+	q.Subscribe(func(noti Notification) { got = append(got, noti.Message) })
+
+	q.AddPlayer([]*Player{{ID: 1, Score: 25.0}})
+	q.AddPlayer([]*Player{{ID: 2, Score: 25.0}})
+	q.AddPlayer([]*Player{{ID: 3, Score: 25.0}})
+	q.RemovePlayer(3, true)
+
+	groups, err := q.ProcMatching()
+	assert.NoError(t, err)
+	assert.Len(t, groups, 1)
+
+	assert.Equal(t, []string{
+		NotifyPartyEnqueued, NotifyPartyEnqueued, NotifyPartyEnqueued, NotifyPartyRemoved,
+		NotifyRoundStarted, NotifyGroupCreated, NotifyMatchWindowChanged, NotifyRoundFinished,
+	}, got)
+
+	// a round without enough players also finishes
+	got = nil
+	q.AddPlayer([]*Player{{ID: 4, Score: 25.0}})
+	_, err = q.ProcMatching()
+	assert.ErrorIs(t, err, ErrNotEnoughPlayer)
+	assert.Equal(t, []string{NotifyPartyEnqueued, NotifyRoundStarted, NotifyRoundFinished}, got)
+}
@@ -0,0 +1,34 @@
+package matchqueue
+
+// Option configures a queue on creation.
+type Option func(*options)
+
+// options are the settings given by options.
+type options struct {
+	clock       Clock
+	constraints []Constraint
+}
+
+// newOptions applies the options over the defaults.
+func newOptions(opts []Option) *options {
+	o := &options{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithConstraints adds constraints which players in a group must satisfy.
+// They are consulted in addition to the constraints of the configuration.
+func WithConstraints(cs ...Constraint) Option {
+	return func(o *options) {
+		o.constraints = append(o.constraints, cs...)
+	}
+}
+
+// WithClock makes the queue use the clock instead of the system clock.
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
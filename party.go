@@ -14,10 +14,12 @@ type party struct {
 
 	// matching factors
 	avgScore, avgScoreBound, avgScoreMod float64
+	deviation                            float64 // deviation of the average rating of players
 	matchWindow                          float64
 
 	// state
-	waitCnt int
+	waitCnt   int
+	lastRetry int // retry count when the matching factors were adjusted
 }
 
 // newParty create a new party of the given players.
@@ -29,7 +31,7 @@ func newParty(q *queue, players []*Player) *party {
 
 	p := &party{
 		q:         q,
-		createdAt: NowFunc(),
+		createdAt: q.now(),
 	}
 
 	p.setPlayers(players)
@@ -43,6 +45,7 @@ func (p *party) setPlayers(players []*Player) {
 	p.avgScore = 0.0
 	p.avgScoreBound = 0.0
 	p.avgScoreMod = 0.0
+	p.deviation = 0.0
 
 	if len(players) == 0 {
 		return
@@ -51,12 +54,22 @@ func (p *party) setPlayers(players []*Player) {
 	p.id = players[0].ID
 
 	// adjust score using party filter
-	sumScore := 0.0
+	sumScore, sumVariance, rated := 0.0, 0.0, 0
 	for _, pl := range p.players {
-		sumScore += pl.Score
+		sumScore += pl.skill()
+		if pl.Rating != nil {
+			sumVariance += pl.deviation() * pl.deviation()
+			rated++
+		}
 	}
 	p.avgScore = p.q.filter.AdjustPartyScore(len(players), sumScore/float64(len(players)))
 
+	// ratings of players are independent, so the variance of their average is the sum of variances / n².
+	// unrated players are not counted, so that they do not make the party look more certain
+	if rated > 0 {
+		p.deviation = math.Sqrt(sumVariance) / float64(rated)
+	}
+
 	// adjust matching factors
 	p.AdjustMatchingFactor(0.0)
 }
@@ -69,9 +82,24 @@ func (p *party) playerIDs() []PlayerID {
 	return ids
 }
 
+// retry returns how many times the party has retried matching.
+// It is the number of rounds or the number of wait steps, by the queue's window growth mode.
+func (p *party) retry() int {
+	if p.q.config.WindowGrowth != WindowGrowthTime {
+		return p.waitCnt
+	}
+
+	wait := p.q.now().Sub(p.createdAt).Seconds()
+	if p.q.config.MaxWaitSec > 0.0 {
+		wait = min(wait, p.q.config.MaxWaitSec)
+	}
+	return int(max(wait, 0.0) / p.q.config.WaitStepSec)
+}
+
 // AdjustMatchingFactor updates the party's matching score and window size.
 func (p *party) AdjustMatchingFactor(matchWindow float64) {
-	oldScore := p.avgScoreMod
+	oldScore, oldRetry := p.avgScoreMod, p.lastRetry
+	p.lastRetry = p.retry()
 
 	// make sure that the party's match score is bound within the queue's score boundary
 	if p.avgScoreBound == 0.0 {
@@ -79,26 +107,54 @@ func (p *party) AdjustMatchingFactor(matchWindow float64) {
 	}
 
 	// modify the party's match score; its score is changed while the party stays longer in the queue
-	p.avgScoreMod = p.q.filter.ModifyScore(p.waitCnt, p.avgScoreBound)
+	p.avgScoreMod = p.q.filter.ModifyScore(p.lastRetry, p.avgScoreBound)
 
-	// if the party's match score updated, also update match window size
-	if oldScore != p.avgScoreMod && matchWindow > 0.0 {
+	// if the party's match score or retry count updated, also update match window size
+	if (oldScore != p.avgScoreMod || oldRetry != p.lastRetry) && matchWindow > 0.0 {
 		p.UpdateWindowSize(matchWindow)
 	}
 }
 
 // UpdateWindowSize updates its matching window size base on the given window. (queue's)
 func (p *party) UpdateWindowSize(matchWindow float64) {
+	oldWindow := p.matchWindow
+
 	p.matchWindow = clamp(
-		p.q.filter.AdjustWindow(p.avgScoreMod, matchWindow)+float64(p.waitCnt)*p.q.config.WindowAdjustPerRetry,
+		p.q.filter.AdjustWindow(p.avgScoreMod, matchWindow)+float64(p.lastRetry)*p.q.config.WindowAdjustPerRetry+p.deviationWindow(),
 		p.q.config.MinMatchWindow, p.q.config.MaxMatchWindow,
 	)
+
+	// the initial window size is not a widening
+	if oldWindow > 0.0 && p.matchWindow > oldWindow {
+		p.q.notify(NotifyPartyWindowWidened, map[string]any{"leader": p.id, "old": oldWindow, "new": p.matchWindow})
+	}
+}
+
+// deviationWindow returns how much the party's window is widened by the uncertainty of its rating.
+// The deviation is converted to the range of the bound score by the filter.
+func (p *party) deviationWindow() float64 {
+	if p.deviation <= 0.0 || p.q.config.DeviationWindowRatio <= 0.0 {
+		return 0.0
+	}
+
+	spread := p.q.filter.BoundScore(p.avgScore+p.deviation) - p.q.filter.BoundScore(p.avgScore-p.deviation)
+	return p.q.config.DeviationWindowRatio * spread / 2.0
 }
 
 // CanMatch checks if the party can match with the target party.
 func (p *party) CanMatch(t *party) bool {
 	scoreDiff := math.Abs(t.avgScoreMod - p.avgScoreMod)
-	return scoreDiff <= p.matchWindow
+	if scoreDiff > p.matchWindow {
+		return false
+	}
+
+	// both parties must have a common datacenter
+	if !p.q.hasDatacenter(p, t) {
+		return false
+	}
+
+	// players of both parties must satisfy constraints of the queue
+	return p.q.allowParties(p, t)
 }
 
 // HasPriorityTo checks if p has higher priority than t.
@@ -2,12 +2,13 @@ package matchqueue
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_newParty(t *testing.T) {
-	q := &queue{filter: newMatchFilter("", "", defaultModRatio)}
+	q := &queue{filter: testMatchFilter("", "", defaultModRatio)}
 	players := []*Player{{ID: 1, Score: 34.0}, {ID: 2, Score: 23.25}, {ID: 3, Score: 22.5}, {ID: 4, Score: 19.25}}
 
 	type args struct {
@@ -68,14 +69,14 @@ func Test_party_AdjustMatchingFactor(t *testing.T) {
 	}{
 		{
 			"simple",
-			&party{avgScore: 24.4, q: &queue{config: Config{MaxMatchWindow: 100.0}, filter: newMatchFilter("", "", defaultModRatio)}},
+			&party{avgScore: 24.4, q: &queue{config: Config{MaxMatchWindow: 100.0}, filter: testMatchFilter("", "", defaultModRatio)}},
 			args{15.0},
 			48.8, 15.0,
 		},
 		{"complicated",
-			&party{avgScore: 24.4, q: &queue{config: Config{MaxMatchWindow: 100.0}, filter: newMatchFilter("curve", "calculated", defaultModRatio)}},
+			&party{avgScore: 24.4, q: &queue{config: Config{MaxMatchWindow: 100.0}, filter: testMatchFilter("curve", "calculated", defaultModRatio)}},
 			args{15.0},
-			47.712116831117335, 14.92154188734874,
+			47.712116831117335, 14.92264102890615,
 		},
 	}
 	for _, tt := range tests {
@@ -87,6 +88,58 @@ func Test_party_AdjustMatchingFactor(t *testing.T) {
 	}
 }
 
+func Test_party_rating(t *testing.T) {
+	q := &queue{config: Config{MaxMatchWindow: 100.0, DeviationWindowRatio: 1.0}, filter: testMatchFilter("", "", defaultModRatio)}
+
+	tests := []struct {
+		name          string
+		players       []*Player
+		wantScore     float64
+		wantDeviation float64
+		wantWindow    float64
+	}{
+		{"score", []*Player{{ID: 1, Score: 25.0}}, 50.0, 0.0, 15.0},
+		{"rating", []*Player{{ID: 1, Score: 10.0, Rating: &Rating{Mean: 25.0, Deviation: 4.0}}}, 50.0, 4.0, 23.0},
+		{"party", []*Player{{ID: 1, Rating: &Rating{Mean: 25.0, Deviation: 6.0}}, {ID: 2, Rating: &Rating{Mean: 25.0, Deviation: 8.0}}}, 75.0, 5.0, 25.0},
+		{"unrated member", []*Player{{ID: 1, Rating: &Rating{Mean: 25.0, Deviation: 6.0}}, {ID: 2, Score: 25.0}}, 75.0, 6.0, 27.0},
+		{"unrated", []*Player{{ID: 1, Score: 25.0}, {ID: 2, Score: 25.0}}, 75.0, 0.0, 15.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParty(q, tt.players)
+			p.UpdateWindowSize(15.0)
+			assert.InDelta(t, tt.wantScore, p.avgScoreMod, 1e-9)
+			assert.InDelta(t, tt.wantDeviation, p.deviation, 1e-9)
+			assert.InDelta(t, tt.wantWindow, p.matchWindow, 1e-9)
+		})
+	}
+
+	// ratings of another scale match the same way with the filter params of the scale
+	fp := DefaultFilterParams()
+	fp.ScoreMid, fp.ScoreRange = 1500.0, 1500.0
+	conf := DefaultConfig()
+	conf.FilterParams = fp
+	scaled := New(conf).(*queue)
+	base := New(DefaultConfig()).(*queue)
+
+	for _, r := range []Rating{{Mean: 1500.0, Deviation: 350.0}, {Mean: 2100.0, Deviation: 60.0}, {Mean: 900.0, Deviation: 120.0}} {
+		p := newParty(scaled, []*Player{{ID: 1, Rating: &r}})
+		p.UpdateWindowSize(scaled.matchWindow)
+		want := newParty(base, []*Player{{ID: 1, Rating: &Rating{Mean: r.Mean / 60.0, Deviation: r.Deviation / 60.0}}})
+		want.UpdateWindowSize(base.matchWindow)
+
+		assert.InDelta(t, want.avgScoreMod, p.avgScoreMod, 1e-9)
+		assert.InDelta(t, want.matchWindow, p.matchWindow, 1e-9)
+		assert.Less(t, p.avgScoreMod, fp.BoundMax)
+	}
+
+	// the window is not widened without the ratio
+	q.config.DeviationWindowRatio = 0.0
+	p := newParty(q, []*Player{{ID: 1, Rating: &Rating{Mean: 25.0, Deviation: 4.0}}})
+	p.UpdateWindowSize(15.0)
+	assert.Equal(t, 15.0, p.matchWindow)
+}
+
 func Test_party_CanMatch(t *testing.T) {
 	type args struct {
 		t *party
@@ -132,3 +185,41 @@ func Test_party_HasPriorityTo(t *testing.T) {
 		})
 	}
 }
+
+func Test_party_retry(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	conf := DefaultConfig()
+	conf.WindowGrowth = WindowGrowthTime
+	conf.WaitStepSec = 10.0
+	conf.MaxWaitSec = 60.0
+	conf.WindowAdjustPerRetry = 2.0
+	conf.MatchingWindowFilter = "simple"
+
+	q := New(conf, WithClock(clock)).(*queue)
+	q.AddPlayer([]*Player{{ID: 1, Score: 25.0}})
+	p := q.parties[0]
+
+	tests := []struct {
+		elapsed    time.Duration
+		wantRetry  int
+		wantWindow float64
+	}{
+		{0, 0, 10.0},
+		{9 * time.Second, 0, 10.0},
+		{10 * time.Second, 1, 12.0},
+		{35 * time.Second, 3, 16.0},
+		{60 * time.Second, 6, 22.0},
+		{10 * time.Minute, 6, 22.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.elapsed.String(), func(t *testing.T) {
+			clock.Set(start.Add(tt.elapsed))
+			p.AdjustMatchingFactor(q.matchWindow)
+
+			assert.Equal(t, tt.wantRetry, p.retry())
+			assert.Equal(t, tt.wantWindow, p.matchWindow)
+		})
+	}
+}
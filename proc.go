@@ -2,7 +2,7 @@ package matchqueue
 
 import (
 	"math"
-	"sort"
+	"time"
 )
 
 // ProcMatching does a matching process.
@@ -11,6 +11,12 @@ func (q *queue) ProcMatching() ([]*Group, error) {
 	q.state.PlayerQueued = q.playerCnt
 	q.state.MatchWindow = q.matchWindow
 
+	q.notify(NotifyRoundStarted, map[string]any{
+		"round":  q.state.Round,
+		"queued": q.playerCnt,
+		"window": q.matchWindow,
+	})
+
 	var created []*Group
 
 	if q.playerCnt > 0 {
@@ -23,6 +29,8 @@ func (q *queue) ProcMatching() ([]*Group, error) {
 		if procCreate {
 			var err error
 			if created, err = q.ProcCreate(); err != nil {
+				// the round finishes without creating any group
+				q.notifyRoundFinished(0)
 				return nil, err
 			} else if len(created) > 0 {
 				q.roundGroupCreated = q.state.Round
@@ -41,15 +49,32 @@ func (q *queue) ProcMatching() ([]*Group, error) {
 				// adjust the party's matching factors due to change of wait count
 				p.AdjustMatchingFactor(q.matchWindow)
 			}
+		} else if q.config.WindowGrowth == WindowGrowthTime {
+			// wait time changes even if no process is done
+			for _, p := range q.parties {
+				p.AdjustMatchingFactor(q.matchWindow)
+			}
 		}
 
 		// adjust queue's match window
 		q.adjustMatchWindow(oldCnt)
 	}
 
+	q.notifyRoundFinished(len(created))
+
 	return created, nil
 }
 
+// notifyRoundFinished notifies the end of the current round with the number of created groups.
+func (q *queue) notifyRoundFinished(created int) {
+	q.notify(NotifyRoundFinished, map[string]any{
+		"round":   q.state.Round,
+		"created": created,
+		"queued":  q.playerCnt,
+		"window":  q.matchWindow,
+	})
+}
+
 // ProcCreate commits process to create groups.
 func (q *queue) ProcCreate() ([]*Group, error) {
 	if q.playerCnt < q.config.MinNumToCreateGroup {
@@ -66,11 +91,20 @@ func (q *queue) ProcCreate() ([]*Group, error) {
 
 			// remove matched parties
 			for _, cand := range candidates {
-				q.RemovePlayer(cand.id, false)
+				if idx, p := q.findParty(cand.id); p != nil {
+					q.removeParty(idx, p)
+				}
 			}
 
 			// a group created
 			q.state.GroupCreated++
+			q.stats.addGroup(g.CreatedAt)
+			for _, gp := range g.Parties {
+				q.state.AddMatched(uint64(gp.WaitTime/time.Second), len(gp.Players))
+				q.stats.addMatched(g.CreatedAt, gp.WaitTime, len(gp.Players))
+			}
+
+			q.notify(NotifyGroupCreated, map[string]any{"group": g})
 		}
 	}
 
@@ -83,6 +117,15 @@ func (q *queue) procCreateImpl(start, count int) (results [][]*party) {
 	// cut party list to [start:start+count]
 	parties := q.partiesSorted[start : start+count]
 
+	maxCnt, minCandidates := q.groupLimits()
+
+	// roles of players in a group must be able to satisfy role rules of all teams
+	var assigner *roleAssigner
+	layouts, _ := q.config.teamLayouts()
+	if teamRules := q.config.teamRoleRules(layouts); teamRules != nil {
+		assigner = newRoleAssigner(groupRoleRules(teamRules))
+	}
+
 	for baseIdx, baseP := range parties {
 		if _, ok := matched[baseP.id]; ok {
 			// already matched
@@ -104,7 +147,7 @@ func (q *queue) procCreateImpl(start, count int) (results [][]*party) {
 				continue
 			}
 
-			if playerCnt+len(p.players) > int(q.config.MaxNumToCreateGroup) {
+			if playerCnt+len(p.players) > maxCnt {
 				continue
 			}
 
@@ -114,17 +157,34 @@ func (q *queue) procCreateImpl(start, count int) (results [][]*party) {
 				continue
 			}
 
+			// all candidates must satisfy constraints of the queue and have a common datacenter together
+			if len(candidates) > 0 && !q.allowParties(append(candidates, p)...) {
+				continue
+			}
+			if len(candidates) > 0 && !q.hasDatacenter(append(candidates, p)...) {
+				continue
+			}
+
+			if assigner != nil && !assigner.fits(partyPlayers(append(candidates, p)), maxCnt-playerCnt-len(p.players)) {
+				continue
+			}
+
 			candidates = append(candidates, p)
 			playerCnt += len(p.players)
 
-			if playerCnt >= q.config.MaxNumToCreateGroup && len(candidates) >= 2 {
+			if playerCnt >= maxCnt && len(candidates) >= minCandidates {
 				// enough players are gathered
 				break
 			}
 		}
 
-		// at least 2 candidates are required (number of team = 2)
-		if playerCnt >= q.config.MinNumToCreateGroup && len(candidates) >= 2 {
+		if assigner != nil && !assigner.fits(partyPlayers(candidates), 0) {
+			// minimums of roles are not satisfied
+			continue
+		}
+
+		// at least one candidate is required for each team
+		if playerCnt >= q.config.MinNumToCreateGroup && len(candidates) >= minCandidates {
 			for _, cand := range candidates {
 				matched[cand.id] = struct{}{}
 			}
@@ -135,29 +195,75 @@ func (q *queue) procCreateImpl(start, count int) (results [][]*party) {
 	return
 }
 
-func (q *queue) newGroup(candidates []*party) *Group {
-	g := &Group{ID: q.idPool + 1, CreatedRound: q.state.Round}
-
-	// sort candidates by number of players, descending
-	sort.Slice(candidates, func(i, j int) bool {
-		return len(candidates[i].players) > len(candidates[j].players)
-	})
+// groupLimits returns the maximum number of players in a group
+// and the minimum number of parties required to create a group.
+func (q *queue) groupLimits() (maxCnt, minCandidates int) {
+	layouts, balanced := q.config.teamLayouts()
+	if balanced {
+		return q.config.MaxNumToCreateGroup, len(layouts)
+	}
 
-	// add candidates to the team of lesser players
-	team := 0
-	for _, cand := range candidates {
-		// if number of players in the current team is bigger than the opposite, change team
-		if len(g.Players[team]) > len(g.Players[1-team]) {
-			team = 1 - team
+	maxCnt = q.config.MaxNumToCreateGroup
+	sumMax := 0
+	for _, l := range layouts {
+		if l.MinSize > 0 {
+			minCandidates++
 		}
-		g.Players[team] = append(g.Players[team], cand.players...)
+		if sumMax >= 0 && l.MaxSize > 0 {
+			sumMax += l.MaxSize
+		} else {
+			// no limit
+			sumMax = -1
+		}
+	}
+	if sumMax >= 0 {
+		maxCnt = min(maxCnt, sumMax)
 	}
 
-	// if number of players of each team differ more than 1, fail to create a group
-	if math.Abs(float64(len(g.Players[0])-len(g.Players[1]))) > 1 {
+	return maxCnt, max(minCandidates, 1)
+}
+
+// newGroup creates a group of the candidates.
+func (q *queue) newGroup(candidates []*party) *Group {
+	// the first candidate is the base party of the group
+	matchWindow := candidates[0].matchWindow
+
+	datacenter, ok := q.selectDatacenter(candidates...)
+	if !ok {
 		return nil
 	}
 
+	teams, roles := q.arrangeTeams(candidates)
+	if teams == nil {
+		return nil
+	}
+
+	now := q.now()
+	g := &Group{
+		ID:           q.idPool + 1,
+		CreatedRound: q.state.Round,
+		CreatedAt:    now,
+		Players:      make([][]*Player, len(teams)),
+		MatchWindow:  matchWindow,
+		Roles:        roles,
+		Datacenter:   datacenter,
+	}
+
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for i, team := range teams {
+		for _, p := range team {
+			g.Players[i] = append(g.Players[i], p.players...)
+			g.Parties = append(g.Parties, GroupParty{Leader: p.id, Players: p.playerIDs(), Team: i, WaitTime: max(now.Sub(p.createdAt), 0)})
+
+			for _, pl := range p.players {
+				lowest, highest = min(lowest, pl.skill()), max(highest, pl.skill())
+			}
+		}
+	}
+	g.ScoreSpread = highest - lowest
+	g.TeamScores = teamScores(g.Players)
+	g.WinProbability = winProbability(g.TeamScores, q.config.FilterParams.orDefault().performanceDeviation())
+
 	q.idPool++
 	return g
 }
@@ -187,6 +293,8 @@ func (q *queue) adjustMatchWindow(oldCnt int) {
 	}
 
 	if q.matchWindow != oldWindow {
+		q.notify(NotifyMatchWindowChanged, map[string]any{"old": oldWindow, "new": q.matchWindow})
+
 		// match window changed; update all parties' window
 		for _, p := range q.parties {
 			p.UpdateWindowSize(q.matchWindow)
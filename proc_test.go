@@ -0,0 +1,123 @@
+package matchqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_queue_ProcCreate_report(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	conf := DefaultConfig()
+	conf.MinNumToCreateGroup = 4
+	conf.MaxNumToCreateGroup = 4
+	conf.InitMatchWindow = conf.MaxMatchWindow
+
+	q := New(conf, WithClock(clock)).(*queue)
+	q.AddPlayer([]*Player{{ID: 1, Score: 20.0}})
+	q.AddPlayer([]*Player{{ID: 2, Score: 30.0}})
+	clock.Advance(10 * time.Second)
+	q.AddPlayer([]*Player{{ID: 3, Score: 24.0}})
+	clock.Advance(10 * time.Second)
+	q.AddPlayer([]*Player{{ID: 4, Score: 26.0}})
+	clock.Advance(10 * time.Second)
+
+	groups, err := q.ProcCreate()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	g := groups[0]
+	assert.Equal(t, start.Add(30*time.Second), g.CreatedAt)
+	assert.Equal(t, 10.0, g.ScoreSpread)
+	assert.Equal(t, []float64{25.0, 25.0}, g.TeamScores)
+	assert.Empty(t, q.parties)
+	assert.Greater(t, g.MatchWindow, 0.0)
+	assert.ElementsMatch(t, []GroupParty{
+		{Leader: 1, Players: []PlayerID{1}, Team: g.Parties[0].Team, WaitTime: 30 * time.Second},
+		{Leader: 2, Players: []PlayerID{2}, Team: g.Parties[0].Team, WaitTime: 30 * time.Second},
+		{Leader: 3, Players: []PlayerID{3}, Team: 1 - g.Parties[0].Team, WaitTime: 20 * time.Second},
+		{Leader: 4, Players: []PlayerID{4}, Team: 1 - g.Parties[0].Team, WaitTime: 10 * time.Second},
+	}, g.Parties)
+
+	// matched players are accumulated in the state
+	state := q.State()
+	assert.Equal(t, 4, state.PlayerMatched)
+	assert.Equal(t, uint64(30+30+20+10), state.WaitTimeAll)
+	assert.Equal(t, uint64(22), state.WaitTimeAvg)
+	assert.Equal(t, uint64(30), state.WaitTimeMax)
+}
+
+func Test_queue_ProcCreate_roles(t *testing.T) {
+	conf := DefaultConfig()
+	conf.MinNumToCreateGroup = 6
+	conf.MaxNumToCreateGroup = 6
+	conf.InitMatchWindow = conf.MaxMatchWindow
+	conf.Roles = []RoleRule{{Role: "tank", Min: 1, Max: 1}, {Role: "healer", Min: 1, Max: 1}}
+
+	t.Run("composition", func(t *testing.T) {
+		q := New(conf).(*queue)
+		for i, role := range []string{"tank", "tank", "tank", "healer", "healer", "dps", "dps", "dps"} {
+			q.AddPlayer([]*Player{{ID: PlayerID(i + 1), Score: 25.0, Roles: []string{role}}})
+		}
+
+		groups, err := q.ProcCreate()
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+
+		g := groups[0]
+		assert.Equal(t, map[PlayerID]string{1: "tank", 2: "tank", 4: "healer", 5: "healer", 6: "dps", 7: "dps"}, g.Roles)
+		for _, team := range g.Players {
+			var roles []string
+			for _, pl := range team {
+				roles = append(roles, g.Roles[pl.ID])
+			}
+			assert.ElementsMatch(t, []string{"tank", "healer", "dps"}, roles)
+		}
+
+		// the third tank is left
+		assert.Len(t, q.parties, 2)
+	})
+
+	t.Run("no roles", func(t *testing.T) {
+		conf := *conf
+		conf.MinNumToCreateGroup, conf.MaxNumToCreateGroup = 10, 10
+		conf.Roles = []RoleRule{{Role: "tank", Min: 1, Max: 1}, {Role: "healer", Min: 1, Max: 2}}
+
+		q := New(&conf).(*queue)
+		for i := range 10 {
+			q.AddPlayer([]*Player{{ID: PlayerID(i + 1), Score: 25.0}})
+		}
+
+		groups, err := q.ProcCreate()
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+
+		g := groups[0]
+		for _, team := range g.Players {
+			counts := map[string]int{}
+			for _, pl := range team {
+				counts[g.Roles[pl.ID]]++
+			}
+			assert.Equal(t, 1, counts["tank"])
+			assert.GreaterOrEqual(t, counts["healer"], 1)
+			assert.LessOrEqual(t, counts["healer"], 2)
+		}
+	})
+
+	t.Run("missing role", func(t *testing.T) {
+		q := New(conf).(*queue)
+		for i := range 8 {
+			q.AddPlayer([]*Player{{ID: PlayerID(i + 1), Score: 25.0, Roles: []string{"dps"}}})
+		}
+		q.AddPlayer([]*Player{{ID: 9, Score: 25.0, Roles: []string{"tank"}}})
+
+		groups, err := q.ProcCreate()
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+		assert.Len(t, q.parties, 9)
+	})
+}
@@ -13,33 +13,58 @@ type (
 
 	Queue interface {
 		// AddPlayers adds players to the queue and updates matching factors for the queue.
-		AddPlayer([]*Player)
+		// If any of the players is already queued, it returns *DuplicatePlayerError
+		// or replaces the party of the player, by the configuration.
+		// If the players violate constraints of the queue, it returns ErrConstraintViolated.
+		AddPlayer([]*Player) error
 
 		// Remove player removes player from the queue.
 		// All players added together will be removed together.
 		RemovePlayer(PlayerID, bool)
 
+		// FindPlayer returns information of the party which has the player.
+		FindPlayer(PlayerID) (PartyInfo, bool)
+
 		// State returns the queue's current state.
 		State() State
 
 		// ProcMatching does a matching process.
 		ProcMatching() ([]*Group, error)
+
+		// Subscribe registers a function which receives notifications of the queue.
+		// The function is called synchronously while the queue is processing,
+		// so it must not call the queue's methods.
+		// The returned function cancels the subscription.
+		Subscribe(func(Notification)) func()
+
+		// Snapshot serializes the queue's internal state.
+		Snapshot() ([]byte, error)
+
+		// Restore replaces the queue's internal state with the snapshot.
+		Restore([]byte) error
+
+		// UpdateConfig replaces the queue's configuration and recalculates matching factors of all parties.
+		UpdateConfig(*Config) error
 	}
 )
 
-var NowFunc = time.Now
-
 type (
 	queue struct {
 		// basic info
 		config Config
+		clock  Clock
 
 		// party
-		parties       []*party // party list sorted by the join order
-		partiesSorted []*party // party list sorted by its priority
+		parties       []*party            // party list sorted by the join order
+		partiesSorted []*party            // party list sorted by its priority
+		members       map[PlayerID]*party // parties of all queued players
 
 		// match filter
-		filter *matchFilter
+		filter Filter
+
+		// constraints
+		constraints    []Constraint // all constraints consulted by matching
+		optConstraints []Constraint // constraints given by options
 
 		// match state
 		matchWindow       float64
@@ -47,37 +72,124 @@ type (
 		roundGroupCreated uint64
 
 		state *State
+		stats *queueStats
 
 		idPool GroupID
+
+		notifier
 	}
 )
 
 var _ Queue = new(queue)
 
 // New creates a new matching queue.
-func New(conf *Config) Queue {
-	q := &queue{config: *conf, state: &State{}}
-	q.Init()
+// It panics if the filter of the configuration cannot be created.
+// Use NewQueue to validate the configuration.
+func New(conf *Config, opts ...Option) Queue {
+	q := newQueue(conf, opts)
+	if err := q.Init(); err != nil {
+		panic("matchqueue: " + err.Error())
+	}
 	return q
 }
 
+// NewQueue creates a new matching queue after validating the configuration.
+func NewQueue(conf *Config, opts ...Option) (Queue, error) {
+	filter, err := conf.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	q := newQueue(conf, opts)
+	if err := q.initWith(filter); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func newQueue(conf *Config, opts []Option) *queue {
+	o := newOptions(opts)
+	return &queue{config: *conf, clock: o.clock, optConstraints: o.constraints, state: &State{}, members: map[PlayerID]*party{}}
+}
+
 // Init initializes the new queue.
 // It sets matching factors using its configuration.
-func (q *queue) Init() {
+func (q *queue) Init() error {
+	filter, err := NewFilter(&q.config)
+	if err != nil {
+		return err
+	}
+	return q.initWith(filter)
+}
+
+// initWith initializes the new queue with the filter created for its configuration.
+func (q *queue) initWith(filter Filter) error {
+	constraints, err := q.buildConstraints(&q.config)
+	if err != nil {
+		return err
+	}
+
 	q.matchWindow = q.config.InitMatchWindow
-	q.filter = newMatchFilter(q.config.ScoreBoundFilter, q.config.MatchingWindowFilter, q.config.ScoreModRatio)
+	q.filter = filter
+	q.constraints = constraints
+	q.stats = newQueueStats(q.config.StatWindowSec)
+	return nil
 }
 
 // implementation of Queue
-func (q *queue) AddPlayer(players []*Player) {
+func (q *queue) AddPlayer(players []*Player) error {
 	if len(players) == 0 {
-		return
+		return nil
+	}
+
+	if !q.allow(players) {
+		return ErrConstraintViolated
+	}
+
+	if err := q.checkDuplicate(players); err != nil {
+		return err
 	}
 
 	p := newParty(q, players)
 	p.UpdateWindowSize(q.matchWindow)
 
 	q.addParty(p)
+
+	q.notify(NotifyPartyEnqueued, map[string]any{
+		"leader":  p.id,
+		"players": p.playerIDs(),
+		"score":   p.avgScoreMod,
+		"window":  p.matchWindow,
+	})
+	return nil
+}
+
+// checkDuplicate checks whether any of the players is already queued.
+// By the duplicate policy, it returns an error or removes parties which have the players.
+func (q *queue) checkDuplicate(players []*Player) error {
+	for i, pl := range players {
+		if slices.ContainsFunc(players[:i], func(t *Player) bool { return t.ID == pl.ID }) {
+			return &DuplicatePlayerError{Player: pl.ID, Leader: players[0].ID}
+		}
+	}
+
+	var replaced []*party
+	for _, pl := range players {
+		p, ok := q.members[pl.ID]
+		if !ok || slices.Contains(replaced, p) {
+			continue
+		}
+		if q.config.DuplicatePolicy != DuplicateReplace {
+			return &DuplicatePlayerError{Player: pl.ID, Leader: p.id}
+		}
+		replaced = append(replaced, p)
+	}
+
+	for _, p := range replaced {
+		idx, _ := q.findParty(p.id)
+		q.dropParty(idx, p, false)
+	}
+	return nil
 }
 
 func (q *queue) addParty(p *party) {
@@ -92,43 +204,134 @@ func (q *queue) addParty(p *party) {
 		return p.HasPriorityTo(q.partiesSorted[i])
 	})
 
+	for _, pl := range p.players {
+		q.members[pl.ID] = p
+	}
+
 	// total number of players in the queue
 	q.playerCnt += len(p.players)
 }
 
-func (q *queue) RemovePlayer(leader PlayerID, updateState bool) {
-	if !leader.IsValid() {
+func (q *queue) RemovePlayer(id PlayerID, updateState bool) {
+	if !id.IsValid() {
 		return
 	}
 
-	idx, p := q.findParty(leader)
+	idx, p := q.findParty(id)
 	if p == nil {
 		return
 	}
 
-	memberCnt := len(p.players)
+	q.dropParty(idx, p, updateState)
+}
 
-	q.parties = slices.Delete(q.parties, idx, idx)
-	q.partiesSorted = slices.DeleteFunc(q.partiesSorted, func(t *party) bool { return t.id == leader })
+// dropParty removes the party at idx of the party list before matched, and notifies it.
+func (q *queue) dropParty(idx int, p *party, updateState bool) {
+	q.removeParty(idx, p)
 
-	// update queued player count
-	q.playerCnt = max(q.playerCnt-memberCnt, 0)
+	now := q.now()
+	waitTime := max(now.Sub(p.createdAt), 0)
 
 	// update state
 	if updateState {
-		waitTime := NowFunc().Sub(p.createdAt)
-		q.state.AddCanceled(uint64(max(waitTime, 0)), memberCnt)
+		q.state.AddCanceled(uint64(waitTime/time.Second), len(p.players))
+		q.stats.addCanceled(now, len(p.players))
+	}
+
+	q.notify(NotifyPartyRemoved, map[string]any{
+		"leader":    p.id,
+		"players":   p.playerIDs(),
+		"canceled":  updateState,
+		"wait_time": waitTime,
+	})
+}
+
+// removeParty removes the party at idx of the party list.
+func (q *queue) removeParty(idx int, p *party) {
+	q.parties = slices.Delete(q.parties, idx, idx+1)
+	q.partiesSorted = slices.DeleteFunc(q.partiesSorted, func(t *party) bool { return t == p })
+	for _, pl := range p.players {
+		delete(q.members, pl.ID)
+	}
+
+	// update queued player count
+	q.playerCnt = max(q.playerCnt-len(p.players), 0)
+}
+
+func (q *queue) UpdateConfig(conf *Config) error {
+	filter, err := conf.validate()
+	if err != nil {
+		return err
+	}
+
+	constraints, err := q.buildConstraints(conf)
+	if err != nil {
+		return err
 	}
+
+	if q.stats != nil && !slices.Equal(q.config.StatWindowSec, conf.StatWindowSec) {
+		q.stats.setWindows(conf.StatWindowSec)
+	}
+
+	q.config = *conf
+	q.filter = filter
+	q.constraints = constraints
+
+	// keep the match window in the new range
+	oldWindow := q.matchWindow
+	q.matchWindow = clamp(q.matchWindow, q.config.MinMatchWindow, q.config.MaxMatchWindow)
+	if q.matchWindow != oldWindow {
+		q.notify(NotifyMatchWindowChanged, map[string]any{"old": oldWindow, "new": q.matchWindow})
+	}
+
+	// recalculate matching factors of all parties with the new filter
+	q.partiesSorted = q.partiesSorted[:0]
+	for _, p := range q.parties {
+		p.setPlayers(p.players)
+		p.UpdateWindowSize(q.matchWindow)
+
+		q.partiesSorted = insertSortedSlice(q.partiesSorted, p, func(i int) bool {
+			return p.HasPriorityTo(q.partiesSorted[i])
+		})
+	}
+
+	return nil
 }
 
+// now returns the current time of the queue's clock.
+func (q *queue) now() time.Time {
+	if q.clock == nil {
+		return time.Now()
+	}
+	return q.clock.Now()
+}
+
+// findParty returns the party which has the player, and its index in the party list.
 func (q *queue) findParty(id PlayerID) (idx int, p *party) {
-	idx = slices.IndexFunc(q.parties, func(t *party) bool { return t.id == id })
-	if idx >= 0 {
-		p = q.parties[idx]
+	p, ok := q.members[id]
+	if !ok {
+		return -1, nil
+	}
+	return slices.Index(q.parties, p), p
+}
+
+func (q *queue) FindPlayer(id PlayerID) (PartyInfo, bool) {
+	_, p := q.findParty(id)
+	if p == nil {
+		return PartyInfo{}, false
 	}
-	return
+
+	return PartyInfo{
+		Leader:   p.id,
+		Players:  p.playerIDs(),
+		WaitTime: max(q.now().Sub(p.createdAt), 0),
+		Score:    p.avgScoreMod,
+		Window:   p.matchWindow,
+	}, true
 }
 
 func (q *queue) State() State {
-	return *q.state
+	s := *q.state
+	q.stats.fill(&s, q.now())
+	return s
 }
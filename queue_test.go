@@ -3,7 +3,10 @@ package matchqueue
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -71,7 +74,7 @@ func (ts *queueTestSuite) TestProcMatching() {
 	ts.Run("match", func() {
 		// groups are created
 		// #2 + #3 vs #6, #5
-		var expected [2][]*Player
+		expected := make([][]*Player, 2)
 		for _, n := range []int{1, 2} {
 			expected[0] = append(expected[0], ts.players[n]...)
 		}
@@ -89,3 +92,121 @@ func (ts *queueTestSuite) TestProcMatching() {
 		ts.Assert().EqualValues(8, got.CreatedRound)
 	})
 }
+
+func Test_queue_UpdateConfig(t *testing.T) {
+	q := New(DefaultConfig()).(*queue)
+	for i, score := range []float64{10.0, 50.0, 30.0} {
+		q.AddPlayer([]*Player{{ID: PlayerID(i + 1), Score: score}})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		conf := DefaultConfig()
+		conf.MinMatchWindow = 100.0
+
+		assert.ErrorIs(t, q.UpdateConfig(conf), ErrInvalidConfig)
+		assert.Equal(t, *DefaultConfig(), q.config)
+	})
+
+	t.Run("updated", func(t *testing.T) {
+		conf := DefaultConfig()
+		conf.ScoreBoundFilter = "simple"
+		conf.MatchingWindowFilter = "simple"
+		conf.InitMatchWindow, conf.MinMatchWindow, conf.MaxMatchWindow = 5.0, 1.0, 6.0
+		conf.ScoreModRatio = []float64{1.0}
+
+		assert.NoError(t, q.UpdateConfig(conf))
+		assert.Equal(t, 6.0, q.matchWindow)
+
+		// factors are calculated with the simple filter
+		for _, p := range q.parties {
+			assert.Equal(t, 2.0*p.players[0].Score, p.avgScoreMod)
+			assert.Equal(t, 6.0, p.matchWindow)
+		}
+
+		// sorted by the priority again
+		var ids []PlayerID
+		for _, p := range q.partiesSorted {
+			ids = append(ids, p.id)
+		}
+		assert.Equal(t, []PlayerID{2, 3, 1}, ids)
+	})
+}
+
+func Test_queue_Duplicate(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	q := New(DefaultConfig(), WithClock(clock)).(*queue)
+
+	var removed []PlayerID
+	q.Subscribe(func(noti Notification) {
+		if noti.Message == NotifyPartyRemoved {
+			removed = append(removed, noti.Data["leader"].(PlayerID))
+		}
+	})
+
+	require.NoError(t, q.AddPlayer([]*Player{{ID: 1, Score: 25.0}, {ID: 2, Score: 25.0}}))
+
+	var dup *DuplicatePlayerError
+	tests := []struct {
+		name    string
+		players []*Player
+		want    DuplicatePlayerError
+	}{
+		{"leader", []*Player{{ID: 1}}, DuplicatePlayerError{Player: 1, Leader: 1}},
+		{"member", []*Player{{ID: 3}, {ID: 2}}, DuplicatePlayerError{Player: 2, Leader: 1}},
+		{"in the party", []*Player{{ID: 3}, {ID: 4}, {ID: 3}}, DuplicatePlayerError{Player: 3, Leader: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := q.AddPlayer(tt.players)
+			assert.ErrorIs(t, err, ErrPlayerQueued)
+			require.ErrorAs(t, err, &dup)
+			assert.Equal(t, tt.want, *dup)
+		})
+	}
+	assert.Equal(t, 2, q.playerCnt)
+	assert.Len(t, q.parties, 1)
+
+	t.Run("find", func(t *testing.T) {
+		clock.Advance(5 * time.Second)
+
+		info, ok := q.FindPlayer(2)
+		require.True(t, ok)
+		assert.Equal(t, PlayerID(1), info.Leader)
+		assert.Equal(t, []PlayerID{1, 2}, info.Players)
+		assert.Equal(t, 5*time.Second, info.WaitTime)
+		assert.Equal(t, q.parties[0].avgScoreMod, info.Score)
+		assert.Equal(t, q.parties[0].matchWindow, info.Window)
+
+		_, ok = q.FindPlayer(3)
+		assert.False(t, ok)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		conf := DefaultConfig()
+		conf.DuplicatePolicy = DuplicateReplace
+		require.NoError(t, q.UpdateConfig(conf))
+
+		require.NoError(t, q.AddPlayer([]*Player{{ID: 3, Score: 25.0}}))
+		require.NoError(t, q.AddPlayer([]*Player{{ID: 2, Score: 30.0}, {ID: 3, Score: 30.0}, {ID: 4, Score: 30.0}}))
+		assert.Equal(t, []PlayerID{1, 3}, removed)
+		assert.Equal(t, 3, q.playerCnt)
+		assert.Equal(t, 0, q.state.PlayerCanceled)
+
+		_, ok := q.FindPlayer(1)
+		assert.False(t, ok)
+		info, ok := q.FindPlayer(4)
+		require.True(t, ok)
+		assert.Equal(t, PlayerID(2), info.Leader)
+
+		// duplicates in the party are always rejected
+		assert.ErrorIs(t, q.AddPlayer([]*Player{{ID: 5}, {ID: 5}}), ErrPlayerQueued)
+	})
+
+	t.Run("remove by member", func(t *testing.T) {
+		q.RemovePlayer(4, true)
+		assert.Equal(t, []PlayerID{1, 3, 2}, removed)
+		assert.Equal(t, 0, q.playerCnt)
+		assert.Empty(t, q.members)
+		assert.Equal(t, 3, q.state.PlayerCanceled)
+	})
+}
@@ -0,0 +1,56 @@
+package rating
+
+import (
+	"math"
+
+	"github.com/scalcor/matchqueue"
+)
+
+// Elo updates ratings by the Elo rating system.
+// A team is rated by the average of its players, and every pair of competitors is a game.
+// Players of a team gain or lose the same amount, and their deviations are not changed.
+type Elo struct {
+	K             float64 // maximum change of a rating in a game against one opponent
+	Scale         float64 // difference of ratings where the stronger one is expected to win 10 times more
+	InitialRating float64 // rating of a player without a rating
+}
+
+var _ Algorithm = new(Elo)
+
+// DefaultElo returns Elo with the parameters used in chess.
+func DefaultElo() *Elo {
+	return &Elo{K: 32.0, Scale: 400.0, InitialRating: 1500.0}
+}
+
+// Expected returns the expected score of a rating against the other.
+func (e *Elo) Expected(rating, other float64) float64 {
+	return 1.0 / (1.0 + math.Pow(10.0, (other-rating)/e.Scale))
+}
+
+func (e *Elo) Initial() matchqueue.Rating {
+	return matchqueue.Rating{Mean: e.InitialRating}
+}
+
+func (e *Elo) Rate(teams [][]matchqueue.Rating, ranks []int) [][]matchqueue.Rating {
+	means := make([]float64, len(teams))
+	for i, team := range teams {
+		means[i], _ = average(team)
+	}
+
+	updated := make([][]matchqueue.Rating, len(teams))
+	for i, team := range teams {
+		// the change is averaged over opponents to keep it within K
+		delta := 0.0
+		for j := range teams {
+			if i != j {
+				delta += score(ranks[i], ranks[j]) - e.Expected(means[i], means[j])
+			}
+		}
+		delta *= e.K / float64(len(teams)-1)
+
+		for _, r := range team {
+			updated[i] = append(updated[i], matchqueue.Rating{Mean: r.Mean + delta, Deviation: r.Deviation})
+		}
+	}
+	return updated
+}
@@ -0,0 +1,35 @@
+package rating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scalcor/matchqueue"
+)
+
+func TestElo_Rate(t *testing.T) {
+	e := DefaultElo()
+
+	tests := []struct {
+		name  string
+		teams [][]matchqueue.Rating
+		ranks []int
+		want  []float64 // means of the first player of each team
+	}{
+		{"win", [][]matchqueue.Rating{{{Mean: 1500.0}}, {{Mean: 1500.0}}}, []int{0, 1}, []float64{1516.0, 1484.0}},
+		{"draw", [][]matchqueue.Rating{{{Mean: 1500.0}}, {{Mean: 1500.0}}}, []int{0, 0}, []float64{1500.0, 1500.0}},
+		{"upset", [][]matchqueue.Rating{{{Mean: 1400.0}}, {{Mean: 1800.0}}}, []int{0, 1}, []float64{1429.09, 1770.91}},
+		{"free for all", [][]matchqueue.Rating{{{Mean: 1500.0}}, {{Mean: 1500.0}}, {{Mean: 1500.0}}}, []int{1, 2, 3}, []float64{1516.0, 1500.0, 1484.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := e.Rate(tt.teams, tt.ranks)
+			for i, want := range tt.want {
+				assert.InDelta(t, want, got[i][0].Mean, 0.01)
+			}
+		})
+	}
+
+	assert.InDelta(t, 0.909, e.Expected(1800.0, 1400.0), 0.001)
+}
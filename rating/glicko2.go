@@ -0,0 +1,118 @@
+package rating
+
+import (
+	"math"
+
+	"github.com/scalcor/matchqueue"
+)
+
+// Glicko2 updates ratings by the Glicko-2 rating system.
+// A match is a rating period where each player plays a game against every other competitor,
+// which is rated as a composite opponent of the average mean and the root mean square deviation of its players.
+//
+// Volatilities are not kept in matchqueue.Rating, so every player starts the period with Volatility.
+type Glicko2 struct {
+	Scale            float64 // ratio of the rating scale to the Glicko-2 scale
+	InitialRating    float64 // rating of a player without a rating
+	InitialDeviation float64 // deviation of a player whose deviation is not positive
+	Volatility       float64 // volatility of players
+	Tau              float64 // constraint of the change of volatility
+}
+
+var _ Algorithm = new(Glicko2)
+
+// DefaultGlicko2 returns Glicko2 with the parameters suggested by Glickman.
+func DefaultGlicko2() *Glicko2 {
+	return &Glicko2{Scale: 173.7178, InitialRating: 1500.0, InitialDeviation: 350.0, Volatility: 0.06, Tau: 0.5}
+}
+
+// convergence tolerance of the volatility
+const glicko2Epsilon = 0.000001
+
+func (gl *Glicko2) Initial() matchqueue.Rating {
+	return matchqueue.Rating{Mean: gl.InitialRating, Deviation: gl.InitialDeviation}
+}
+
+func (gl *Glicko2) Rate(teams [][]matchqueue.Rating, ranks []int) [][]matchqueue.Rating {
+	// composite opponents in the Glicko-2 scale
+	mus, phis := make([]float64, len(teams)), make([]float64, len(teams))
+	for i, team := range teams {
+		mean, deviation := average(gl.withDeviation(team))
+		mus[i], phis[i] = mean/gl.Scale, deviation/gl.Scale
+	}
+
+	updated := make([][]matchqueue.Rating, len(teams))
+	for i, team := range teams {
+		for _, r := range gl.withDeviation(team) {
+			mu, phi := r.Mean/gl.Scale, r.Deviation/gl.Scale
+
+			// estimated variance and improvement of the rating
+			var invV, sum float64
+			for j := range teams {
+				if i == j {
+					continue
+				}
+				g := 1.0 / math.Sqrt(1.0+3.0*phis[j]*phis[j]/(math.Pi*math.Pi))
+				e := 1.0 / (1.0 + math.Exp(-g*(mu-mus[j])))
+				invV += g * g * e * (1.0 - e)
+				sum += g * (score(ranks[i], ranks[j]) - e)
+			}
+			v := 1.0 / invV
+			sigma := gl.volatility(phi, v, v*sum)
+
+			phiStar := math.Sqrt(phi*phi + sigma*sigma)
+			newPhi := 1.0 / math.Sqrt(1.0/(phiStar*phiStar)+invV)
+			newMu := mu + newPhi*newPhi*sum
+
+			updated[i] = append(updated[i], matchqueue.Rating{Mean: newMu * gl.Scale, Deviation: newPhi * gl.Scale})
+		}
+	}
+	return updated
+}
+
+// withDeviation returns the ratings replacing deviations which are not positive with the initial deviation.
+func (gl *Glicko2) withDeviation(ratings []matchqueue.Rating) []matchqueue.Rating {
+	replaced := make([]matchqueue.Rating, len(ratings))
+	for i, r := range ratings {
+		if r.Deviation <= 0.0 {
+			r.Deviation = gl.InitialDeviation
+		}
+		replaced[i] = r
+	}
+	return replaced
+}
+
+// volatility returns the new volatility by the Illinois algorithm.
+func (gl *Glicko2) volatility(phi, v, delta float64) float64 {
+	a := math.Log(gl.Volatility * gl.Volatility)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-d)/(2.0*d*d) - (x-a)/(gl.Tau*gl.Tau)
+	}
+
+	lo := a
+	var hi float64
+	if delta*delta > phi*phi+v {
+		hi = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*gl.Tau) < 0.0 {
+			k++
+		}
+		hi = a - k*gl.Tau
+	}
+
+	fLo, fHi := f(lo), f(hi)
+	for math.Abs(hi-lo) > glicko2Epsilon {
+		c := lo + (lo-hi)*fLo/(fHi-fLo)
+		fc := f(c)
+		if fc*fHi <= 0.0 {
+			lo, fLo = hi, fHi
+		} else {
+			fLo /= 2.0
+		}
+		hi, fHi = c, fc
+	}
+	return math.Exp(lo / 2.0)
+}
@@ -0,0 +1,38 @@
+package rating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scalcor/matchqueue"
+)
+
+func TestGlicko2_Rate(t *testing.T) {
+	gl := DefaultGlicko2()
+
+	// the example of Glickman: the player beats the first opponent and loses to the others
+	got := gl.Rate([][]matchqueue.Rating{
+		{{Mean: 1500.0, Deviation: 200.0}},
+		{{Mean: 1400.0, Deviation: 30.0}},
+		{{Mean: 1550.0, Deviation: 100.0}},
+		{{Mean: 1700.0, Deviation: 300.0}},
+	}, []int{2, 3, 1, 1})
+	assert.InDelta(t, 1464.06, got[0][0].Mean, 0.01)
+	assert.InDelta(t, 151.52, got[0][0].Deviation, 0.01)
+
+	// teams are composite opponents, and deviations which are not positive are the initial deviation
+	got = gl.Rate([][]matchqueue.Rating{
+		{{Mean: 1500.0}, {Mean: 1500.0, Deviation: 350.0}},
+		{{Mean: 1500.0, Deviation: 350.0}, {Mean: 1500.0, Deviation: 350.0}},
+	}, []int{0, 1})
+	assert.Equal(t, got[0][0], got[0][1])
+	assert.Greater(t, got[0][0].Mean, 1500.0)
+	assert.Less(t, got[0][0].Deviation, 350.0)
+	assert.InDelta(t, 3000.0, got[0][0].Mean+got[1][0].Mean, 1e-9)
+
+	// a draw of equal players changes only deviations
+	got = gl.Rate([][]matchqueue.Rating{{{Mean: 1500.0, Deviation: 100.0}}, {{Mean: 1500.0, Deviation: 100.0}}}, []int{0, 0})
+	assert.InDelta(t, 1500.0, got[0][0].Mean, 1e-9)
+	assert.Less(t, got[0][0].Deviation, 100.0)
+}
@@ -0,0 +1,150 @@
+// Package rating computes new ratings of players from the result of a match.
+//
+// A match is given as a matchqueue.Group with its Outcome: the winning team, a draw,
+// or placements of players for a free-for-all match. Ratings of players are taken from
+// Player.Rating, or the initial rating of the algorithm if the player has no rating,
+// and are updated by one of the algorithms:
+//
+//	Elo        pairwise expected scores of teams; deviations are kept
+//	Glicko2    Glicko-2 against each other team as a composite opponent
+//	TrueSkill  TrueSkill-style Bayesian update of teams in the full pairing of Weng and Lin
+//
+// Default parameters of Elo and Glicko2 are for ratings in the usual scale around 1500,
+// and those of TrueSkill are for ratings around 25, the default score of the queue.
+// Player.Score is not used since it may be in another scale than the algorithm.
+package rating
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/scalcor/matchqueue"
+)
+
+var ErrInvalidOutcome = errors.New("invalid outcome")
+
+// Algorithm updates ratings of competitors of a match.
+type Algorithm interface {
+	// Initial returns the rating of a player without a rating.
+	Initial() matchqueue.Rating
+	// Rate returns new ratings of the competitors.
+	// teams[i] are ratings of players of the i-th competitor, and ranks[i] is its rank;
+	// a lower rank is better and equal ranks are draws.
+	Rate(teams [][]matchqueue.Rating, ranks []int) [][]matchqueue.Rating
+}
+
+// Outcome is the result of a match.
+type Outcome struct {
+	winner     int                         // index of the winning team; -1 for a draw
+	placements map[matchqueue.PlayerID]int // placements of players in a free-for-all match
+}
+
+// Win returns the outcome that the team, an index of Group.Players, won and all the other teams lost.
+func Win(team int) Outcome {
+	return Outcome{winner: team}
+}
+
+// Draw returns the outcome that all teams drew.
+func Draw() Outcome {
+	return Outcome{winner: -1}
+}
+
+// Placements returns the outcome of a free-for-all match, where every player competes alone regardless of teams.
+// A lower placement is better and equal placements are draws.
+func Placements(placements map[matchqueue.PlayerID]int) Outcome {
+	return Outcome{placements: placements}
+}
+
+// competitors returns the players and the rank of each competitor of the group by the outcome.
+func (o Outcome) competitors(g *matchqueue.Group) ([][]*matchqueue.Player, []int, error) {
+	var (
+		teams [][]*matchqueue.Player
+		ranks []int
+	)
+
+	if o.placements != nil {
+		for _, players := range g.Players {
+			for _, pl := range players {
+				place, ok := o.placements[pl.ID]
+				if !ok {
+					return nil, nil, fmt.Errorf("%w: no placement of player %d", ErrInvalidOutcome, pl.ID)
+				}
+				teams = append(teams, []*matchqueue.Player{pl})
+				ranks = append(ranks, place)
+			}
+		}
+		if len(teams) != len(o.placements) {
+			return nil, nil, fmt.Errorf("%w: placements of players not in the group", ErrInvalidOutcome)
+		}
+	} else {
+		if o.winner < -1 || o.winner >= len(g.Players) {
+			return nil, nil, fmt.Errorf("%w: no team %d", ErrInvalidOutcome, o.winner)
+		}
+		for i, players := range g.Players {
+			if len(players) == 0 {
+				continue
+			}
+			rank := 1
+			if o.winner == -1 || o.winner == i {
+				rank = 0
+			}
+			teams = append(teams, players)
+			ranks = append(ranks, rank)
+		}
+	}
+
+	if len(teams) < 2 {
+		return nil, nil, fmt.Errorf("%w: less than 2 competitors", ErrInvalidOutcome)
+	}
+	return teams, ranks, nil
+}
+
+// Update returns new ratings of players of the group by the outcome.
+// A player without a rating is rated from the initial rating of the algorithm.
+func Update(alg Algorithm, g *matchqueue.Group, o Outcome) (map[matchqueue.PlayerID]matchqueue.Rating, error) {
+	teams, ranks, err := o.competitors(g)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make([][]matchqueue.Rating, len(teams))
+	for i, players := range teams {
+		for _, pl := range players {
+			r := alg.Initial()
+			if pl.Rating != nil {
+				r = *pl.Rating
+			}
+			ratings[i] = append(ratings[i], r)
+		}
+	}
+
+	updated := map[matchqueue.PlayerID]matchqueue.Rating{}
+	for i, rs := range alg.Rate(ratings, ranks) {
+		for j, r := range rs {
+			updated[teams[i][j].ID] = r
+		}
+	}
+	return updated, nil
+}
+
+// score returns the actual score of a competitor of the rank against an opponent of the other rank.
+func score(rank, other int) float64 {
+	switch {
+	case rank < other:
+		return 1.0
+	case rank > other:
+		return 0.0
+	}
+	return 0.5
+}
+
+// average returns the average mean and the root mean square deviation of the ratings.
+func average(ratings []matchqueue.Rating) (mean, deviation float64) {
+	for _, r := range ratings {
+		mean += r.Mean
+		deviation += r.Deviation * r.Deviation
+	}
+	n := float64(len(ratings))
+	return mean / n, math.Sqrt(deviation / n)
+}
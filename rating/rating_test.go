@@ -0,0 +1,103 @@
+package rating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scalcor/matchqueue"
+)
+
+func Test_Outcome_competitors(t *testing.T) {
+	g := &matchqueue.Group{Players: [][]*matchqueue.Player{{{ID: 1}, {ID: 2}}, {{ID: 3}}, {}}}
+
+	tests := []struct {
+		name      string
+		o         Outcome
+		wantTeams [][]matchqueue.PlayerID
+		wantRanks []int
+		wantErr   bool
+	}{
+		{"win", Win(1), [][]matchqueue.PlayerID{{1, 2}, {3}}, []int{1, 0}, false},
+		{"draw", Draw(), [][]matchqueue.PlayerID{{1, 2}, {3}}, []int{0, 0}, false},
+		{"placements", Placements(map[matchqueue.PlayerID]int{1: 3, 2: 1, 3: 1}), [][]matchqueue.PlayerID{{1}, {2}, {3}}, []int{3, 1, 1}, false},
+		{"unknown team", Win(3), nil, nil, true},
+		{"missing placement", Placements(map[matchqueue.PlayerID]int{1: 1, 2: 2}), nil, nil, true},
+		{"unknown player", Placements(map[matchqueue.PlayerID]int{1: 1, 2: 2, 3: 3, 4: 4}), nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams, ranks, err := tt.o.competitors(g)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidOutcome)
+				return
+			}
+			require.NoError(t, err)
+
+			var ids [][]matchqueue.PlayerID
+			for _, team := range teams {
+				var teamIDs []matchqueue.PlayerID
+				for _, pl := range team {
+					teamIDs = append(teamIDs, pl.ID)
+				}
+				ids = append(ids, teamIDs)
+			}
+			assert.Equal(t, tt.wantTeams, ids)
+			assert.Equal(t, tt.wantRanks, ranks)
+		})
+	}
+
+	// a group of a single team cannot be rated
+	_, _, err := Win(0).competitors(&matchqueue.Group{Players: [][]*matchqueue.Player{{{ID: 1}, {ID: 2}}}})
+	assert.ErrorIs(t, err, ErrInvalidOutcome)
+}
+
+func TestUpdate(t *testing.T) {
+	g := &matchqueue.Group{Players: [][]*matchqueue.Player{
+		{{ID: 1, Score: 25.0}, {ID: 2, Score: 0.0, Rating: &matchqueue.Rating{Mean: 1600.0, Deviation: 100.0}}},
+		{{ID: 3, Rating: &matchqueue.Rating{Mean: 1550.0}}, {ID: 4, Rating: &matchqueue.Rating{Mean: 1550.0}}},
+	}}
+
+	ratings, err := Update(DefaultElo(), g, Win(0))
+	require.NoError(t, err)
+	require.Len(t, ratings, 4)
+	assert.InDelta(t, 1516.0, ratings[1].Mean, 1e-9)
+	assert.InDelta(t, 1616.0, ratings[2].Mean, 1e-9)
+	assert.Equal(t, 100.0, ratings[2].Deviation)
+	assert.InDelta(t, 1534.0, ratings[3].Mean, 1e-9)
+
+	_, err = Update(DefaultElo(), g, Win(2))
+	assert.ErrorIs(t, err, ErrInvalidOutcome)
+}
+
+func TestUpdate_queue(t *testing.T) {
+	// unrated players of a default queue are rated from the initial rating, not from their scores around 25
+	conf := matchqueue.DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.NumRoundToCreateGroup = 1
+	conf.InitMatchWindow = conf.MaxMatchWindow
+
+	q := matchqueue.New(conf)
+	require.NoError(t, q.AddPlayer([]*matchqueue.Player{{ID: 1, Score: 20.0}}))
+	require.NoError(t, q.AddPlayer([]*matchqueue.Player{{ID: 2, Score: 30.0}}))
+	groups, err := q.ProcMatching()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	g := groups[0]
+	require.Len(t, g.Players, 2)
+	winner, loser := g.Players[0][0].ID, g.Players[1][0].ID
+
+	ratings, err := Update(DefaultElo(), g, Win(0))
+	require.NoError(t, err)
+	assert.InDelta(t, 1516.0, ratings[winner].Mean, 1e-9)
+	assert.InDelta(t, 1484.0, ratings[loser].Mean, 1e-9)
+
+	ratings, err = Update(DefaultGlicko2(), g, Win(0))
+	require.NoError(t, err)
+	assert.InDelta(t, 1662.31, ratings[winner].Mean, 0.01)
+	assert.InDelta(t, 1337.69, ratings[loser].Mean, 0.01)
+	assert.InDelta(t, 290.32, ratings[winner].Deviation, 0.01)
+	assert.InDelta(t, 290.32, ratings[loser].Deviation, 0.01)
+}
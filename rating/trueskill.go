@@ -0,0 +1,119 @@
+package rating
+
+import (
+	"math"
+
+	"github.com/scalcor/matchqueue"
+)
+
+// TrueSkill updates ratings in the way of TrueSkill without its factor graph.
+// Every pair of competitors is updated as a two-team TrueSkill game and the updates are summed up,
+// as the Thurstone-Mosteller full pairing model of Weng and Lin without its damping of variances.
+// Performance of a team is the sum of performances of its players.
+type TrueSkill struct {
+	Beta             float64 // deviation of the performance of a player
+	InitialRating    float64 // rating of a player without a rating
+	InitialDeviation float64 // deviation of a player whose deviation is not positive
+	Tau              float64 // dynamics added to the deviation before a match
+	DrawMargin       float64 // difference of performances regarded as a draw
+}
+
+var _ Algorithm = new(TrueSkill)
+
+// DefaultTrueSkill returns TrueSkill with the parameters of TrueSkill for ratings starting at 25.
+// The draw margin is for the draw probability 0.1 of a game between two players.
+func DefaultTrueSkill() *TrueSkill {
+	return &TrueSkill{Beta: 25.0 / 6.0, InitialRating: 25.0, InitialDeviation: 25.0 / 3.0, Tau: 25.0 / 300.0, DrawMargin: 0.7404}
+}
+
+// minimum ratio to keep the variance of a player
+const trueSkillKappa = 0.0001
+
+func (ts *TrueSkill) Initial() matchqueue.Rating {
+	return matchqueue.Rating{Mean: ts.InitialRating, Deviation: ts.InitialDeviation}
+}
+
+func (ts *TrueSkill) Rate(teams [][]matchqueue.Rating, ranks []int) [][]matchqueue.Rating {
+	// variances of players after dynamics, and the mean and the variance of each team
+	variances := make([][]float64, len(teams))
+	mus, sigmaSqs := make([]float64, len(teams)), make([]float64, len(teams))
+	for i, team := range teams {
+		for _, r := range team {
+			deviation := r.Deviation
+			if deviation <= 0.0 {
+				deviation = ts.InitialDeviation
+			}
+			variance := deviation*deviation + ts.Tau*ts.Tau
+			variances[i] = append(variances[i], variance)
+			mus[i] += r.Mean
+			sigmaSqs[i] += variance
+		}
+	}
+
+	updated := make([][]matchqueue.Rating, len(teams))
+	for i, team := range teams {
+		// update of the mean (omega) and the variance (delta) of the team
+		var omega, delta float64
+		for q := range teams {
+			if q == i {
+				continue
+			}
+			c := math.Sqrt(sigmaSqs[i] + sigmaSqs[q] + float64(len(teams[i])+len(teams[q]))*ts.Beta*ts.Beta)
+			t := ts.DrawMargin / c
+
+			var v, w float64
+			switch {
+			case ranks[i] < ranks[q]:
+				v, w = vWin((mus[i]-mus[q])/c, t)
+			case ranks[i] > ranks[q]:
+				v, w = vWin((mus[q]-mus[i])/c, t)
+				v = -v
+			default:
+				v, w = vDraw((mus[i]-mus[q])/c, t)
+			}
+			omega += sigmaSqs[i] / c * v
+			delta += sigmaSqs[i] / (c * c) * w
+		}
+
+		for j, r := range team {
+			ratio := variances[i][j] / sigmaSqs[i]
+			variance := variances[i][j] * max(1.0-ratio*delta, trueSkillKappa)
+			updated[i] = append(updated[i], matchqueue.Rating{Mean: r.Mean + ratio*omega, Deviation: math.Sqrt(variance)})
+		}
+	}
+	return updated
+}
+
+// vWin returns the corrections of the mean and the variance for a win by the difference x and the draw margin t.
+func vWin(x, t float64) (v, w float64) {
+	d := x - t
+	cdf := normalCDF(d)
+	if cdf < 1e-12 {
+		// limits of a very unexpected win
+		return -d, 1.0
+	}
+	v = normalPDF(d) / cdf
+	return v, v * (v + d)
+}
+
+// vDraw returns the corrections of the mean and the variance for a draw by the difference x and the draw margin t.
+func vDraw(x, t float64) (v, w float64) {
+	denom := normalCDF(t-x) - normalCDF(-t-x)
+	if denom < 1e-12 {
+		// limits of a draw of a very small margin
+		return -x, 1.0
+	}
+	v = (normalPDF(-t-x) - normalPDF(t-x)) / denom
+	w = ((t-x)*normalPDF(t-x)+(t+x)*normalPDF(-t-x))/denom + v*v
+	return v, w
+}
+
+// normalPDF is the probability density function of the standard normal distribution.
+func normalPDF(x float64) float64 {
+	return math.Exp(-x*x/2.0) / math.Sqrt(2.0*math.Pi)
+}
+
+// normalCDF is the cumulative distribution function of the standard normal distribution.
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
@@ -0,0 +1,52 @@
+package rating
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scalcor/matchqueue"
+)
+
+func TestTrueSkill_Rate(t *testing.T) {
+	ts := DefaultTrueSkill()
+	newcomer := matchqueue.Rating{Mean: 25.0}
+
+	// results of the two-player game of TrueSkill
+	got := ts.Rate([][]matchqueue.Rating{{newcomer}, {newcomer}}, []int{0, 1})
+	assert.InDelta(t, 29.396, got[0][0].Mean, 0.001)
+	assert.InDelta(t, 7.171, got[0][0].Deviation, 0.001)
+	assert.InDelta(t, 20.604, got[1][0].Mean, 0.001)
+	assert.InDelta(t, 7.171, got[1][0].Deviation, 0.001)
+
+	got = ts.Rate([][]matchqueue.Rating{{newcomer}, {newcomer}}, []int{0, 0})
+	assert.InDelta(t, 25.0, got[0][0].Mean, 0.001)
+	assert.InDelta(t, 6.458, got[0][0].Deviation, 0.001)
+
+	// results of the 2 vs 2 game of TrueSkill, whose draw margin is for 4 players
+	got = (&TrueSkill{Beta: ts.Beta, InitialDeviation: ts.InitialDeviation, Tau: ts.Tau, DrawMargin: ts.DrawMargin * math.Sqrt2}).
+		Rate([][]matchqueue.Rating{{newcomer, newcomer}, {newcomer, newcomer}}, []int{0, 1})
+	for _, r := range got[0] {
+		assert.InDelta(t, 28.108, r.Mean, 0.001)
+		assert.InDelta(t, 7.774, r.Deviation, 0.001)
+	}
+	for _, r := range got[1] {
+		assert.InDelta(t, 21.892, r.Mean, 0.001)
+		assert.InDelta(t, 7.774, r.Deviation, 0.001)
+	}
+
+	// an uncertain player of a team changes more
+	got = ts.Rate([][]matchqueue.Rating{
+		{{Mean: 25.0, Deviation: 2.0}, {Mean: 25.0, Deviation: 8.0}},
+		{{Mean: 25.0, Deviation: 5.0}, {Mean: 25.0, Deviation: 5.0}},
+	}, []int{0, 1})
+	assert.Greater(t, got[0][1].Mean, got[0][0].Mean)
+	assert.Greater(t, got[0][0].Mean, 25.0)
+	assert.Less(t, got[1][0].Mean, 25.0)
+
+	// an unexpected win does not break the update
+	got = ts.Rate([][]matchqueue.Rating{{{Mean: 0.0, Deviation: 1.0}}, {{Mean: 100.0, Deviation: 1.0}}}, []int{0, 1})
+	assert.Greater(t, got[0][0].Mean, 0.0)
+	assert.Less(t, got[1][0].Mean, 100.0)
+}
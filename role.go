@@ -0,0 +1,211 @@
+package matchqueue
+
+import (
+	"math"
+	"slices"
+)
+
+// RoleRule limits the number of players who take the role in a team.
+type RoleRule struct {
+	Role string `json:"role" yaml:"role"`
+	Min  int    `json:"min" yaml:"min"`
+	Max  int    `json:"max" yaml:"max"` // 0 means no limit
+}
+
+// teamRoleRules returns the role rules of each team.
+// It returns nil if no role rule is configured.
+func (c *Config) teamRoleRules(layouts []TeamLayout) [][]RoleRule {
+	rules := make([][]RoleRule, len(layouts))
+	found := false
+	for i, l := range layouts {
+		rules[i] = c.Roles
+		if len(l.Roles) > 0 {
+			rules[i] = l.Roles
+		}
+		found = found || len(rules[i]) > 0
+	}
+	if !found {
+		return nil
+	}
+	return rules
+}
+
+// groupRoleRules merges role rules of all teams into the rules of a whole group.
+// A role not limited in any of the teams is not limited in the group.
+func groupRoleRules(teamRules [][]RoleRule) []RoleRule {
+	var rules []RoleRule
+	for _, tr := range teamRules {
+		for _, r := range tr {
+			if !slices.ContainsFunc(rules, func(t RoleRule) bool { return t.Role == r.Role }) {
+				rules = append(rules, RoleRule{Role: r.Role})
+			}
+		}
+	}
+
+	for i := range rules {
+		unlimited := false
+		for _, tr := range teamRules {
+			idx := slices.IndexFunc(tr, func(t RoleRule) bool { return t.Role == rules[i].Role })
+			if idx < 0 || tr[idx].Max == 0 {
+				unlimited = true
+				if idx < 0 {
+					continue
+				}
+			}
+			rules[i].Min += tr[idx].Min
+			rules[i].Max += tr[idx].Max
+		}
+		if unlimited {
+			rules[i].Max = 0
+		}
+	}
+	return rules
+}
+
+// restRole is the implicit role of a player who takes none of the roles of the rules.
+// It is not limited, as other roles not in the rules.
+const restRole = ""
+
+// roleAssigner assigns roles to players satisfying role rules.
+//
+// Assignment is a bipartite matching between players and roles where each role has capacity.
+// Roles are filled up to their minimum first, then up to their maximum,
+// so the minimums are satisfied whenever possible.
+type roleAssigner struct {
+	rules []RoleRule
+
+	// state of an assignment
+	roles   []string // roles of rules, then other roles of players and restRole which are not limited
+	caps    []int
+	loads   []int
+	options [][]int // candidate roles of each player
+	taken   []int   // role of each player; -1 if not assigned
+	visited []bool
+}
+
+func newRoleAssigner(rules []RoleRule) *roleAssigner {
+	return &roleAssigner{rules: rules}
+}
+
+// assign assigns roles to the players.
+// It returns the role of each player, and the number of players
+// still required to satisfy the minimums of the rules.
+// ok is false if any of the players cannot take a role.
+func (a *roleAssigner) assign(players []*Player) (roles []string, deficit int, ok bool) {
+	a.roles = a.roles[:0]
+	for _, r := range a.rules {
+		a.roles = append(a.roles, r.Role)
+	}
+
+	a.options = a.options[:0]
+	for _, pl := range players {
+		var opts []int
+		roles := pl.Roles
+		if len(roles) == 0 {
+			// the player can take any role of the rules, or the rest
+			for i := range a.rules {
+				opts = append(opts, i)
+			}
+			roles = []string{restRole}
+		}
+		for _, role := range roles {
+			idx := slices.Index(a.roles, role)
+			if idx < 0 {
+				a.roles = append(a.roles, role)
+				idx = len(a.roles) - 1
+			}
+			opts = append(opts, idx)
+		}
+		a.options = append(a.options, opts)
+	}
+
+	a.caps = resize(a.caps, len(a.roles))
+	a.loads = resize(a.loads, len(a.roles))
+	a.visited = resize(a.visited, len(a.roles))
+	a.taken = resize(a.taken, len(players))
+	for i := range a.taken {
+		a.taken[i] = -1
+	}
+
+	// fill minimums first, then maximums;
+	// an augmentation never decreases the load of a role, so filled minimums are kept
+	for phase := range 2 {
+		for i := range a.roles {
+			switch {
+			case phase == 0 && i >= len(a.rules):
+				a.caps[i] = 0
+			case phase == 0:
+				a.caps[i] = a.rules[i].Min
+			case i >= len(a.rules):
+				a.caps[i] = math.MaxInt
+			case a.rules[i].Max == 0:
+				a.caps[i] = math.MaxInt
+			default:
+				a.caps[i] = a.rules[i].Max
+			}
+		}
+
+		for i := range players {
+			if a.taken[i] >= 0 {
+				continue
+			}
+			clear(a.visited)
+			a.augment(i)
+		}
+	}
+
+	ok = true
+	roles = make([]string, len(players))
+	for i, r := range a.taken {
+		if r < 0 {
+			ok = false
+			continue
+		}
+		roles[i] = a.roles[r]
+	}
+	for i, r := range a.rules {
+		deficit += max(r.Min-a.loads[i], 0)
+	}
+	return roles, deficit, ok
+}
+
+// augment finds a role of the player, moving other players to their other roles if needed.
+func (a *roleAssigner) augment(player int) bool {
+	for _, r := range a.options[player] {
+		if a.visited[r] {
+			continue
+		}
+		a.visited[r] = true
+
+		if a.loads[r] < a.caps[r] {
+			a.taken[player] = r
+			a.loads[r]++
+			return true
+		}
+
+		for other, taken := range a.taken {
+			if taken == r && a.augment(other) {
+				// the other player moved to another role; the player takes its place in r
+				a.taken[player] = r
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fits checks if roles can be assigned to the players
+// so that the minimums of the rules are satisfied with the free slots.
+func (a *roleAssigner) fits(players []*Player, free int) bool {
+	_, deficit, ok := a.assign(players)
+	return ok && deficit <= free
+}
+
+func resize[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+	s = s[:n]
+	clear(s)
+	return s
+}
@@ -0,0 +1,56 @@
+package matchqueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_roleAssigner(t *testing.T) {
+	// 1 tank, 1-2 healers and the rest damage
+	rules := []RoleRule{{Role: "tank", Min: 1, Max: 1}, {Role: "healer", Min: 1, Max: 2}}
+
+	newPlayers := func(roles ...[]string) []*Player {
+		var players []*Player
+		for i, r := range roles {
+			players = append(players, &Player{ID: PlayerID(i + 1), Roles: r})
+		}
+		return players
+	}
+
+	tests := []struct {
+		name        string
+		players     []*Player
+		want        []string
+		wantDeficit int
+		wantOK      bool
+	}{
+		{"exact", newPlayers([]string{"tank"}, []string{"healer"}, []string{"dps"}), []string{"tank", "healer", "dps"}, 0, true},
+		{"flexible", newPlayers([]string{"healer", "tank"}, []string{"healer"}), []string{"tank", "healer"}, 0, true},
+		{"moved", newPlayers([]string{"tank", "healer"}, []string{"tank"}), []string{"healer", "tank"}, 0, true},
+		{"any role", newPlayers(nil, []string{"healer"}), []string{"tank", "healer"}, 0, true},
+		{"no roles", newPlayers(nil, nil, nil, nil, nil), []string{"", "", "healer", "healer", "tank"}, 0, true},
+		{"rest with roles", newPlayers([]string{"tank"}, nil, []string{"healer"}, nil), []string{"tank", "", "healer", "healer"}, 0, true},
+		{"missing", newPlayers([]string{"dps"}, []string{"dps"}), []string{"dps", "dps"}, 2, true},
+		{"too many tanks", newPlayers([]string{"tank"}, []string{"tank"}, []string{"healer"}), []string{"tank", "", "healer"}, 0, false},
+		{"prefer minimum", newPlayers([]string{"tank", "dps"}, []string{"dps", "healer"}), []string{"tank", "healer"}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, deficit, ok := newRoleAssigner(rules).assign(tt.players)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantDeficit, deficit)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func Test_groupRoleRules(t *testing.T) {
+	teamRules := [][]RoleRule{
+		{{Role: "tank", Min: 1, Max: 1}, {Role: "healer", Min: 1, Max: 2}},
+		{{Role: "tank", Min: 1, Max: 1}, {Role: "healer", Min: 0, Max: 0}},
+		{{Role: "tank", Min: 0, Max: 2}},
+	}
+
+	assert.Equal(t, []RoleRule{{Role: "tank", Min: 2, Max: 4}, {Role: "healer", Min: 1, Max: 0}}, groupRoleRules(teamRules))
+}
@@ -0,0 +1,150 @@
+package rpc
+
+import (
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/scalcor/matchqueue"
+	pb "github.com/scalcor/matchqueue/rpc/matchqueuepb"
+)
+
+func toPlayer(pl *matchqueue.Player, role string) *pb.Player {
+	msg := &pb.Player{Id: uint64(pl.ID), Score: pl.Score, Roles: pl.Roles, Role: role, Latency: pl.Latency}
+	if pl.Rating != nil {
+		msg.Rating = &pb.Rating{Mean: pl.Rating.Mean, Deviation: pl.Rating.Deviation}
+	}
+	for name, v := range pl.Attributes {
+		if attr := toAttribute(v); attr != nil {
+			if msg.Attributes == nil {
+				msg.Attributes = map[string]*pb.AttributeValue{}
+			}
+			msg.Attributes[name] = attr
+		}
+	}
+	return msg
+}
+
+func fromPlayer(pl *pb.Player) *matchqueue.Player {
+	player := &matchqueue.Player{
+		ID:      matchqueue.PlayerID(pl.GetId()),
+		Score:   pl.GetScore(),
+		Roles:   pl.GetRoles(),
+		Latency: pl.GetLatency(),
+	}
+	if r := pl.GetRating(); r != nil {
+		player.Rating = &matchqueue.Rating{Mean: r.GetMean(), Deviation: r.GetDeviation()}
+	}
+	for name, attr := range pl.GetAttributes() {
+		if player.Attributes == nil {
+			player.Attributes = map[string]any{}
+		}
+		switch v := attr.GetValue().(type) {
+		case *pb.AttributeValue_Text:
+			player.Attributes[name] = v.Text
+		case *pb.AttributeValue_Number:
+			player.Attributes[name] = v.Number
+		case *pb.AttributeValue_Set:
+			player.Attributes[name] = v.Set.GetValues()
+		}
+	}
+	return player
+}
+
+// toAttribute converts the value of an attribute. It returns nil for values of unknown types.
+func toAttribute(v any) *pb.AttributeValue {
+	switch v := v.(type) {
+	case string:
+		return &pb.AttributeValue{Value: &pb.AttributeValue_Text{Text: v}}
+	case float64:
+		return &pb.AttributeValue{Value: &pb.AttributeValue_Number{Number: v}}
+	case int:
+		return &pb.AttributeValue{Value: &pb.AttributeValue_Number{Number: float64(v)}}
+	case []string:
+		return &pb.AttributeValue{Value: &pb.AttributeValue_Set{Set: &pb.StringSet{Values: v}}}
+	case []any:
+		set := &pb.StringSet{}
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return nil
+			}
+			set.Values = append(set.Values, s)
+		}
+		return &pb.AttributeValue{Value: &pb.AttributeValue_Set{Set: set}}
+	}
+	return nil
+}
+
+func toGroup(g *matchqueue.Group) *pb.Group {
+	msg := &pb.Group{
+		Id:           uint64(g.ID),
+		CreatedRound: g.CreatedRound,
+		CreatedAt:    timestamppb.New(g.CreatedAt),
+		ScoreSpread:  g.ScoreSpread,
+		MatchWindow:  g.MatchWindow,
+		Datacenter:   g.Datacenter,
+	}
+
+	for i, players := range g.Players {
+		team := &pb.Team{}
+		for _, pl := range players {
+			team.Players = append(team.Players, toPlayer(pl, g.Roles[pl.ID]))
+		}
+		if i < len(g.TeamScores) {
+			team.Score = g.TeamScores[i]
+		}
+		if i < len(g.WinProbability) {
+			team.WinProbability = g.WinProbability[i]
+		}
+		msg.Teams = append(msg.Teams, team)
+	}
+
+	for _, gp := range g.Parties {
+		party := &pb.GroupParty{
+			Leader:   uint64(gp.Leader),
+			Team:     int32(gp.Team),
+			WaitTime: durationpb.New(gp.WaitTime),
+		}
+		for _, id := range gp.Players {
+			party.Players = append(party.Players, uint64(id))
+		}
+		msg.Parties = append(msg.Parties, party)
+	}
+
+	return msg
+}
+
+func toState(s matchqueue.State) *pb.QueueState {
+	msg := &pb.QueueState{
+		Round:               s.Round,
+		PlayerQueued:        int64(s.PlayerQueued),
+		MatchWindow:         s.MatchWindow,
+		GroupCreated:        int64(s.GroupCreated),
+		WaitTimeAll:         s.WaitTimeAll,
+		WaitTimeAvg:         s.WaitTimeAvg,
+		WaitTimeMax:         s.WaitTimeMax,
+		PlayerMatched:       int64(s.PlayerMatched),
+		CanceledWaitTimeAll: s.CanceledWaitTimeAll,
+		CanceledWaitTimeAvg: s.CanceledWaitTimeAvg,
+		CanceledWaitTimeMax: s.CanceledWaitTimeMax,
+		PlayerCanceled:      int64(s.PlayerCanceled),
+		WaitTimeP50:         s.WaitTimeP50,
+		WaitTimeP90:         s.WaitTimeP90,
+		WaitTimeP99:         s.WaitTimeP99,
+	}
+
+	for _, w := range s.Windows {
+		msg.Windows = append(msg.Windows, &pb.WindowState{
+			Period:         w.Period,
+			GroupCreated:   int64(w.GroupCreated),
+			PlayerMatched:  int64(w.PlayerMatched),
+			PlayerCanceled: int64(w.PlayerCanceled),
+			MatchRate:      w.MatchRate,
+			WaitTimeP50:    w.WaitTimeP50,
+			WaitTimeP90:    w.WaitTimeP90,
+			WaitTimeP99:    w.WaitTimeP99,
+		})
+	}
+
+	return msg
+}
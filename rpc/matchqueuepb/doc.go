@@ -0,0 +1,4 @@
+// Package matchqueuepb contains the protobuf messages and the gRPC service of the matching queue.
+package matchqueuepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative matchqueue.proto
@@ -0,0 +1,1484 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: matchqueue.proto
+
+package matchqueuepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PartyEvent_Type int32
+
+const (
+	PartyEvent_TYPE_UNSPECIFIED    PartyEvent_Type = 0
+	PartyEvent_TYPE_WINDOW_WIDENED PartyEvent_Type = 1
+	PartyEvent_TYPE_MATCHED        PartyEvent_Type = 2
+	PartyEvent_TYPE_REMOVED        PartyEvent_Type = 3
+)
+
+// Enum value maps for PartyEvent_Type.
+var (
+	PartyEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_WINDOW_WIDENED",
+		2: "TYPE_MATCHED",
+		3: "TYPE_REMOVED",
+	}
+	PartyEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED":    0,
+		"TYPE_WINDOW_WIDENED": 1,
+		"TYPE_MATCHED":        2,
+		"TYPE_REMOVED":        3,
+	}
+)
+
+func (x PartyEvent_Type) Enum() *PartyEvent_Type {
+	p := new(PartyEvent_Type)
+	*p = x
+	return p
+}
+
+func (x PartyEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PartyEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_matchqueue_proto_enumTypes[0].Descriptor()
+}
+
+func (PartyEvent_Type) Type() protoreflect.EnumType {
+	return &file_matchqueue_proto_enumTypes[0]
+}
+
+func (x PartyEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PartyEvent_Type.Descriptor instead.
+func (PartyEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{16, 0}
+}
+
+type Player struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Score float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// roles the player can take; empty for any role
+	Roles []string `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	// role assigned to the player; set in groups if roles are configured
+	Role string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	// attributes used by constraints of the queue
+	Attributes map[string]*AttributeValue `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// latency (millisecond) to each datacenter; empty if the player can play in any datacenter
+	Latency map[string]float64 `protobuf:"bytes,6,rep,name=latency,proto3" json:"latency,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	// skill rating of the player; if set, its mean is used as the score
+	Rating        *Rating `protobuf:"bytes,7,opt,name=rating,proto3" json:"rating,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	mi := &file_matchqueue_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Player) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Player) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Player) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *Player) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Player) GetAttributes() map[string]*AttributeValue {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *Player) GetLatency() map[string]float64 {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *Player) GetRating() *Rating {
+	if x != nil {
+		return x.Rating
+	}
+	return nil
+}
+
+type Rating struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mean          float64                `protobuf:"fixed64,1,opt,name=mean,proto3" json:"mean,omitempty"`
+	Deviation     float64                `protobuf:"fixed64,2,opt,name=deviation,proto3" json:"deviation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rating) Reset() {
+	*x = Rating{}
+	mi := &file_matchqueue_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rating) ProtoMessage() {}
+
+func (x *Rating) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rating.ProtoReflect.Descriptor instead.
+func (*Rating) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Rating) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *Rating) GetDeviation() float64 {
+	if x != nil {
+		return x.Deviation
+	}
+	return 0
+}
+
+type AttributeValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*AttributeValue_Text
+	//	*AttributeValue_Number
+	//	*AttributeValue_Set
+	Value         isAttributeValue_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttributeValue) Reset() {
+	*x = AttributeValue{}
+	mi := &file_matchqueue_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttributeValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttributeValue) ProtoMessage() {}
+
+func (x *AttributeValue) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttributeValue.ProtoReflect.Descriptor instead.
+func (*AttributeValue) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AttributeValue) GetValue() isAttributeValue_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *AttributeValue) GetText() string {
+	if x != nil {
+		if x, ok := x.Value.(*AttributeValue_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+func (x *AttributeValue) GetNumber() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*AttributeValue_Number); ok {
+			return x.Number
+		}
+	}
+	return 0
+}
+
+func (x *AttributeValue) GetSet() *StringSet {
+	if x != nil {
+		if x, ok := x.Value.(*AttributeValue_Set); ok {
+			return x.Set
+		}
+	}
+	return nil
+}
+
+type isAttributeValue_Value interface {
+	isAttributeValue_Value()
+}
+
+type AttributeValue_Text struct {
+	Text string `protobuf:"bytes,1,opt,name=text,proto3,oneof"`
+}
+
+type AttributeValue_Number struct {
+	Number float64 `protobuf:"fixed64,2,opt,name=number,proto3,oneof"`
+}
+
+type AttributeValue_Set struct {
+	Set *StringSet `protobuf:"bytes,3,opt,name=set,proto3,oneof"`
+}
+
+func (*AttributeValue_Text) isAttributeValue_Value() {}
+
+func (*AttributeValue_Number) isAttributeValue_Value() {}
+
+func (*AttributeValue_Set) isAttributeValue_Value() {}
+
+type StringSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StringSet) Reset() {
+	*x = StringSet{}
+	mi := &file_matchqueue_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StringSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringSet) ProtoMessage() {}
+
+func (x *StringSet) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringSet.ProtoReflect.Descriptor instead.
+func (*StringSet) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StringSet) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type AddPlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Players       []*Player              `protobuf:"bytes,2,rep,name=players,proto3" json:"players,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPlayerRequest) Reset() {
+	*x = AddPlayerRequest{}
+	mi := &file_matchqueue_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPlayerRequest) ProtoMessage() {}
+
+func (x *AddPlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPlayerRequest.ProtoReflect.Descriptor instead.
+func (*AddPlayerRequest) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AddPlayerRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *AddPlayerRequest) GetPlayers() []*Player {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+type AddPlayerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Leader        uint64                 `protobuf:"varint,1,opt,name=leader,proto3" json:"leader,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPlayerResponse) Reset() {
+	*x = AddPlayerResponse{}
+	mi := &file_matchqueue_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPlayerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPlayerResponse) ProtoMessage() {}
+
+func (x *AddPlayerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPlayerResponse.ProtoReflect.Descriptor instead.
+func (*AddPlayerResponse) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AddPlayerResponse) GetLeader() uint64 {
+	if x != nil {
+		return x.Leader
+	}
+	return 0
+}
+
+type RemovePlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Leader        uint64                 `protobuf:"varint,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePlayerRequest) Reset() {
+	*x = RemovePlayerRequest{}
+	mi := &file_matchqueue_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePlayerRequest) ProtoMessage() {}
+
+func (x *RemovePlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePlayerRequest.ProtoReflect.Descriptor instead.
+func (*RemovePlayerRequest) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RemovePlayerRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *RemovePlayerRequest) GetLeader() uint64 {
+	if x != nil {
+		return x.Leader
+	}
+	return 0
+}
+
+type RemovePlayerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePlayerResponse) Reset() {
+	*x = RemovePlayerResponse{}
+	mi := &file_matchqueue_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePlayerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePlayerResponse) ProtoMessage() {}
+
+func (x *RemovePlayerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePlayerResponse.ProtoReflect.Descriptor instead.
+func (*RemovePlayerResponse) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{7}
+}
+
+type StateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StateRequest) Reset() {
+	*x = StateRequest{}
+	mi := &file_matchqueue_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateRequest) ProtoMessage() {}
+
+func (x *StateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateRequest.ProtoReflect.Descriptor instead.
+func (*StateRequest) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StateRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+type QueueState struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Round               uint64                 `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	PlayerQueued        int64                  `protobuf:"varint,2,opt,name=player_queued,json=playerQueued,proto3" json:"player_queued,omitempty"`
+	MatchWindow         float64                `protobuf:"fixed64,3,opt,name=match_window,json=matchWindow,proto3" json:"match_window,omitempty"`
+	GroupCreated        int64                  `protobuf:"varint,4,opt,name=group_created,json=groupCreated,proto3" json:"group_created,omitempty"`
+	WaitTimeAll         uint64                 `protobuf:"varint,5,opt,name=wait_time_all,json=waitTimeAll,proto3" json:"wait_time_all,omitempty"`
+	WaitTimeAvg         uint64                 `protobuf:"varint,6,opt,name=wait_time_avg,json=waitTimeAvg,proto3" json:"wait_time_avg,omitempty"`
+	WaitTimeMax         uint64                 `protobuf:"varint,7,opt,name=wait_time_max,json=waitTimeMax,proto3" json:"wait_time_max,omitempty"`
+	PlayerMatched       int64                  `protobuf:"varint,8,opt,name=player_matched,json=playerMatched,proto3" json:"player_matched,omitempty"`
+	CanceledWaitTimeAll uint64                 `protobuf:"varint,9,opt,name=canceled_wait_time_all,json=canceledWaitTimeAll,proto3" json:"canceled_wait_time_all,omitempty"`
+	CanceledWaitTimeAvg uint64                 `protobuf:"varint,10,opt,name=canceled_wait_time_avg,json=canceledWaitTimeAvg,proto3" json:"canceled_wait_time_avg,omitempty"`
+	CanceledWaitTimeMax uint64                 `protobuf:"varint,11,opt,name=canceled_wait_time_max,json=canceledWaitTimeMax,proto3" json:"canceled_wait_time_max,omitempty"`
+	PlayerCanceled      int64                  `protobuf:"varint,12,opt,name=player_canceled,json=playerCanceled,proto3" json:"player_canceled,omitempty"`
+	WaitTimeP50         uint64                 `protobuf:"varint,13,opt,name=wait_time_p50,json=waitTimeP50,proto3" json:"wait_time_p50,omitempty"`
+	WaitTimeP90         uint64                 `protobuf:"varint,14,opt,name=wait_time_p90,json=waitTimeP90,proto3" json:"wait_time_p90,omitempty"`
+	WaitTimeP99         uint64                 `protobuf:"varint,15,opt,name=wait_time_p99,json=waitTimeP99,proto3" json:"wait_time_p99,omitempty"`
+	Windows             []*WindowState         `protobuf:"bytes,16,rep,name=windows,proto3" json:"windows,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *QueueState) Reset() {
+	*x = QueueState{}
+	mi := &file_matchqueue_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueState) ProtoMessage() {}
+
+func (x *QueueState) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueState.ProtoReflect.Descriptor instead.
+func (*QueueState) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *QueueState) GetRound() uint64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *QueueState) GetPlayerQueued() int64 {
+	if x != nil {
+		return x.PlayerQueued
+	}
+	return 0
+}
+
+func (x *QueueState) GetMatchWindow() float64 {
+	if x != nil {
+		return x.MatchWindow
+	}
+	return 0
+}
+
+func (x *QueueState) GetGroupCreated() int64 {
+	if x != nil {
+		return x.GroupCreated
+	}
+	return 0
+}
+
+func (x *QueueState) GetWaitTimeAll() uint64 {
+	if x != nil {
+		return x.WaitTimeAll
+	}
+	return 0
+}
+
+func (x *QueueState) GetWaitTimeAvg() uint64 {
+	if x != nil {
+		return x.WaitTimeAvg
+	}
+	return 0
+}
+
+func (x *QueueState) GetWaitTimeMax() uint64 {
+	if x != nil {
+		return x.WaitTimeMax
+	}
+	return 0
+}
+
+func (x *QueueState) GetPlayerMatched() int64 {
+	if x != nil {
+		return x.PlayerMatched
+	}
+	return 0
+}
+
+func (x *QueueState) GetCanceledWaitTimeAll() uint64 {
+	if x != nil {
+		return x.CanceledWaitTimeAll
+	}
+	return 0
+}
+
+func (x *QueueState) GetCanceledWaitTimeAvg() uint64 {
+	if x != nil {
+		return x.CanceledWaitTimeAvg
+	}
+	return 0
+}
+
+func (x *QueueState) GetCanceledWaitTimeMax() uint64 {
+	if x != nil {
+		return x.CanceledWaitTimeMax
+	}
+	return 0
+}
+
+func (x *QueueState) GetPlayerCanceled() int64 {
+	if x != nil {
+		return x.PlayerCanceled
+	}
+	return 0
+}
+
+func (x *QueueState) GetWaitTimeP50() uint64 {
+	if x != nil {
+		return x.WaitTimeP50
+	}
+	return 0
+}
+
+func (x *QueueState) GetWaitTimeP90() uint64 {
+	if x != nil {
+		return x.WaitTimeP90
+	}
+	return 0
+}
+
+func (x *QueueState) GetWaitTimeP99() uint64 {
+	if x != nil {
+		return x.WaitTimeP99
+	}
+	return 0
+}
+
+func (x *QueueState) GetWindows() []*WindowState {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+type WindowState struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Period         uint64                 `protobuf:"varint,1,opt,name=period,proto3" json:"period,omitempty"`
+	GroupCreated   int64                  `protobuf:"varint,2,opt,name=group_created,json=groupCreated,proto3" json:"group_created,omitempty"`
+	PlayerMatched  int64                  `protobuf:"varint,3,opt,name=player_matched,json=playerMatched,proto3" json:"player_matched,omitempty"`
+	PlayerCanceled int64                  `protobuf:"varint,4,opt,name=player_canceled,json=playerCanceled,proto3" json:"player_canceled,omitempty"`
+	MatchRate      float64                `protobuf:"fixed64,5,opt,name=match_rate,json=matchRate,proto3" json:"match_rate,omitempty"`
+	WaitTimeP50    uint64                 `protobuf:"varint,6,opt,name=wait_time_p50,json=waitTimeP50,proto3" json:"wait_time_p50,omitempty"`
+	WaitTimeP90    uint64                 `protobuf:"varint,7,opt,name=wait_time_p90,json=waitTimeP90,proto3" json:"wait_time_p90,omitempty"`
+	WaitTimeP99    uint64                 `protobuf:"varint,8,opt,name=wait_time_p99,json=waitTimeP99,proto3" json:"wait_time_p99,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WindowState) Reset() {
+	*x = WindowState{}
+	mi := &file_matchqueue_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WindowState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WindowState) ProtoMessage() {}
+
+func (x *WindowState) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WindowState.ProtoReflect.Descriptor instead.
+func (*WindowState) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WindowState) GetPeriod() uint64 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+func (x *WindowState) GetGroupCreated() int64 {
+	if x != nil {
+		return x.GroupCreated
+	}
+	return 0
+}
+
+func (x *WindowState) GetPlayerMatched() int64 {
+	if x != nil {
+		return x.PlayerMatched
+	}
+	return 0
+}
+
+func (x *WindowState) GetPlayerCanceled() int64 {
+	if x != nil {
+		return x.PlayerCanceled
+	}
+	return 0
+}
+
+func (x *WindowState) GetMatchRate() float64 {
+	if x != nil {
+		return x.MatchRate
+	}
+	return 0
+}
+
+func (x *WindowState) GetWaitTimeP50() uint64 {
+	if x != nil {
+		return x.WaitTimeP50
+	}
+	return 0
+}
+
+func (x *WindowState) GetWaitTimeP90() uint64 {
+	if x != nil {
+		return x.WaitTimeP90
+	}
+	return 0
+}
+
+func (x *WindowState) GetWaitTimeP99() uint64 {
+	if x != nil {
+		return x.WaitTimeP99
+	}
+	return 0
+}
+
+type WatchGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchGroupsRequest) Reset() {
+	*x = WatchGroupsRequest{}
+	mi := &file_matchqueue_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchGroupsRequest) ProtoMessage() {}
+
+func (x *WatchGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchGroupsRequest.ProtoReflect.Descriptor instead.
+func (*WatchGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchGroupsRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+type Team struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Players        []*Player              `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Score          float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	WinProbability float64                `protobuf:"fixed64,3,opt,name=win_probability,json=winProbability,proto3" json:"win_probability,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_matchqueue_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Team) GetPlayers() []*Player {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *Team) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Team) GetWinProbability() float64 {
+	if x != nil {
+		return x.WinProbability
+	}
+	return 0
+}
+
+type GroupParty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Leader        uint64                 `protobuf:"varint,1,opt,name=leader,proto3" json:"leader,omitempty"`
+	Players       []uint64               `protobuf:"varint,2,rep,packed,name=players,proto3" json:"players,omitempty"`
+	Team          int32                  `protobuf:"varint,3,opt,name=team,proto3" json:"team,omitempty"`
+	WaitTime      *durationpb.Duration   `protobuf:"bytes,4,opt,name=wait_time,json=waitTime,proto3" json:"wait_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupParty) Reset() {
+	*x = GroupParty{}
+	mi := &file_matchqueue_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupParty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupParty) ProtoMessage() {}
+
+func (x *GroupParty) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupParty.ProtoReflect.Descriptor instead.
+func (*GroupParty) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GroupParty) GetLeader() uint64 {
+	if x != nil {
+		return x.Leader
+	}
+	return 0
+}
+
+func (x *GroupParty) GetPlayers() []uint64 {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *GroupParty) GetTeam() int32 {
+	if x != nil {
+		return x.Team
+	}
+	return 0
+}
+
+func (x *GroupParty) GetWaitTime() *durationpb.Duration {
+	if x != nil {
+		return x.WaitTime
+	}
+	return nil
+}
+
+type Group struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Teams        []*Team                `protobuf:"bytes,2,rep,name=teams,proto3" json:"teams,omitempty"`
+	CreatedRound uint64                 `protobuf:"varint,3,opt,name=created_round,json=createdRound,proto3" json:"created_round,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Parties      []*GroupParty          `protobuf:"bytes,5,rep,name=parties,proto3" json:"parties,omitempty"`
+	ScoreSpread  float64                `protobuf:"fixed64,6,opt,name=score_spread,json=scoreSpread,proto3" json:"score_spread,omitempty"`
+	MatchWindow  float64                `protobuf:"fixed64,7,opt,name=match_window,json=matchWindow,proto3" json:"match_window,omitempty"`
+	// datacenter selected for the group; empty if latencies are not considered
+	Datacenter    string `protobuf:"bytes,8,opt,name=datacenter,proto3" json:"datacenter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Group) Reset() {
+	*x = Group{}
+	mi := &file_matchqueue_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Group) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Group) ProtoMessage() {}
+
+func (x *Group) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Group.ProtoReflect.Descriptor instead.
+func (*Group) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Group) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Group) GetTeams() []*Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+func (x *Group) GetCreatedRound() uint64 {
+	if x != nil {
+		return x.CreatedRound
+	}
+	return 0
+}
+
+func (x *Group) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Group) GetParties() []*GroupParty {
+	if x != nil {
+		return x.Parties
+	}
+	return nil
+}
+
+func (x *Group) GetScoreSpread() float64 {
+	if x != nil {
+		return x.ScoreSpread
+	}
+	return 0
+}
+
+func (x *Group) GetMatchWindow() float64 {
+	if x != nil {
+		return x.MatchWindow
+	}
+	return 0
+}
+
+func (x *Group) GetDatacenter() string {
+	if x != nil {
+		return x.Datacenter
+	}
+	return ""
+}
+
+type WatchPartyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queue         string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Leader        uint64                 `protobuf:"varint,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPartyRequest) Reset() {
+	*x = WatchPartyRequest{}
+	mi := &file_matchqueue_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPartyRequest) ProtoMessage() {}
+
+func (x *WatchPartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPartyRequest.ProtoReflect.Descriptor instead.
+func (*WatchPartyRequest) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchPartyRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *WatchPartyRequest) GetLeader() uint64 {
+	if x != nil {
+		return x.Leader
+	}
+	return 0
+}
+
+type PartyEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  PartyEvent_Type        `protobuf:"varint,1,opt,name=type,proto3,enum=matchqueue.v1.PartyEvent_Type" json:"type,omitempty"`
+	// window of the party; set on TYPE_WINDOW_WIDENED
+	Window float64 `protobuf:"fixed64,2,opt,name=window,proto3" json:"window,omitempty"`
+	// the created group; set on TYPE_MATCHED
+	Group *Group `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+	// whether the party is canceled; set on TYPE_REMOVED
+	Canceled bool `protobuf:"varint,4,opt,name=canceled,proto3" json:"canceled,omitempty"`
+	// wait time of the party; set on TYPE_MATCHED and TYPE_REMOVED
+	WaitTime      *durationpb.Duration `protobuf:"bytes,5,opt,name=wait_time,json=waitTime,proto3" json:"wait_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PartyEvent) Reset() {
+	*x = PartyEvent{}
+	mi := &file_matchqueue_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartyEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyEvent) ProtoMessage() {}
+
+func (x *PartyEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_matchqueue_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyEvent.ProtoReflect.Descriptor instead.
+func (*PartyEvent) Descriptor() ([]byte, []int) {
+	return file_matchqueue_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PartyEvent) GetType() PartyEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return PartyEvent_TYPE_UNSPECIFIED
+}
+
+func (x *PartyEvent) GetWindow() float64 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *PartyEvent) GetGroup() *Group {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+func (x *PartyEvent) GetCanceled() bool {
+	if x != nil {
+		return x.Canceled
+	}
+	return false
+}
+
+func (x *PartyEvent) GetWaitTime() *durationpb.Duration {
+	if x != nil {
+		return x.WaitTime
+	}
+	return nil
+}
+
+var File_matchqueue_proto protoreflect.FileDescriptor
+
+const file_matchqueue_proto_rawDesc = "" +
+	"\n" +
+	"\x10matchqueue.proto\x12\rmatchqueue.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa6\x03\n" +
+	"\x06Player\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12\x14\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12E\n" +
+	"\n" +
+	"attributes\x18\x05 \x03(\v2%.matchqueue.v1.Player.AttributesEntryR\n" +
+	"attributes\x12<\n" +
+	"\alatency\x18\x06 \x03(\v2\".matchqueue.v1.Player.LatencyEntryR\alatency\x12-\n" +
+	"\x06rating\x18\a \x01(\v2\x15.matchqueue.v1.RatingR\x06rating\x1a\\\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x123\n" +
+	"\x05value\x18\x02 \x01(\v2\x1d.matchqueue.v1.AttributeValueR\x05value:\x028\x01\x1a:\n" +
+	"\fLatencyEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\":\n" +
+	"\x06Rating\x12\x12\n" +
+	"\x04mean\x18\x01 \x01(\x01R\x04mean\x12\x1c\n" +
+	"\tdeviation\x18\x02 \x01(\x01R\tdeviation\"w\n" +
+	"\x0eAttributeValue\x12\x14\n" +
+	"\x04text\x18\x01 \x01(\tH\x00R\x04text\x12\x18\n" +
+	"\x06number\x18\x02 \x01(\x01H\x00R\x06number\x12,\n" +
+	"\x03set\x18\x03 \x01(\v2\x18.matchqueue.v1.StringSetH\x00R\x03setB\a\n" +
+	"\x05value\"#\n" +
+	"\tStringSet\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"Y\n" +
+	"\x10AddPlayerRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12/\n" +
+	"\aplayers\x18\x02 \x03(\v2\x15.matchqueue.v1.PlayerR\aplayers\"+\n" +
+	"\x11AddPlayerResponse\x12\x16\n" +
+	"\x06leader\x18\x01 \x01(\x04R\x06leader\"C\n" +
+	"\x13RemovePlayerRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x16\n" +
+	"\x06leader\x18\x02 \x01(\x04R\x06leader\"\x16\n" +
+	"\x14RemovePlayerResponse\"$\n" +
+	"\fStateRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\"\x8c\x05\n" +
+	"\n" +
+	"QueueState\x12\x14\n" +
+	"\x05round\x18\x01 \x01(\x04R\x05round\x12#\n" +
+	"\rplayer_queued\x18\x02 \x01(\x03R\fplayerQueued\x12!\n" +
+	"\fmatch_window\x18\x03 \x01(\x01R\vmatchWindow\x12#\n" +
+	"\rgroup_created\x18\x04 \x01(\x03R\fgroupCreated\x12\"\n" +
+	"\rwait_time_all\x18\x05 \x01(\x04R\vwaitTimeAll\x12\"\n" +
+	"\rwait_time_avg\x18\x06 \x01(\x04R\vwaitTimeAvg\x12\"\n" +
+	"\rwait_time_max\x18\a \x01(\x04R\vwaitTimeMax\x12%\n" +
+	"\x0eplayer_matched\x18\b \x01(\x03R\rplayerMatched\x123\n" +
+	"\x16canceled_wait_time_all\x18\t \x01(\x04R\x13canceledWaitTimeAll\x123\n" +
+	"\x16canceled_wait_time_avg\x18\n" +
+	" \x01(\x04R\x13canceledWaitTimeAvg\x123\n" +
+	"\x16canceled_wait_time_max\x18\v \x01(\x04R\x13canceledWaitTimeMax\x12'\n" +
+	"\x0fplayer_canceled\x18\f \x01(\x03R\x0eplayerCanceled\x12\"\n" +
+	"\rwait_time_p50\x18\r \x01(\x04R\vwaitTimeP50\x12\"\n" +
+	"\rwait_time_p90\x18\x0e \x01(\x04R\vwaitTimeP90\x12\"\n" +
+	"\rwait_time_p99\x18\x0f \x01(\x04R\vwaitTimeP99\x124\n" +
+	"\awindows\x18\x10 \x03(\v2\x1a.matchqueue.v1.WindowStateR\awindows\"\xa5\x02\n" +
+	"\vWindowState\x12\x16\n" +
+	"\x06period\x18\x01 \x01(\x04R\x06period\x12#\n" +
+	"\rgroup_created\x18\x02 \x01(\x03R\fgroupCreated\x12%\n" +
+	"\x0eplayer_matched\x18\x03 \x01(\x03R\rplayerMatched\x12'\n" +
+	"\x0fplayer_canceled\x18\x04 \x01(\x03R\x0eplayerCanceled\x12\x1d\n" +
+	"\n" +
+	"match_rate\x18\x05 \x01(\x01R\tmatchRate\x12\"\n" +
+	"\rwait_time_p50\x18\x06 \x01(\x04R\vwaitTimeP50\x12\"\n" +
+	"\rwait_time_p90\x18\a \x01(\x04R\vwaitTimeP90\x12\"\n" +
+	"\rwait_time_p99\x18\b \x01(\x04R\vwaitTimeP99\"*\n" +
+	"\x12WatchGroupsRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\"v\n" +
+	"\x04Team\x12/\n" +
+	"\aplayers\x18\x01 \x03(\v2\x15.matchqueue.v1.PlayerR\aplayers\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12'\n" +
+	"\x0fwin_probability\x18\x03 \x01(\x01R\x0ewinProbability\"\x8a\x01\n" +
+	"\n" +
+	"GroupParty\x12\x16\n" +
+	"\x06leader\x18\x01 \x01(\x04R\x06leader\x12\x18\n" +
+	"\aplayers\x18\x02 \x03(\x04R\aplayers\x12\x12\n" +
+	"\x04team\x18\x03 \x01(\x05R\x04team\x126\n" +
+	"\twait_time\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bwaitTime\"\xbd\x02\n" +
+	"\x05Group\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12)\n" +
+	"\x05teams\x18\x02 \x03(\v2\x13.matchqueue.v1.TeamR\x05teams\x12#\n" +
+	"\rcreated_round\x18\x03 \x01(\x04R\fcreatedRound\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x123\n" +
+	"\aparties\x18\x05 \x03(\v2\x19.matchqueue.v1.GroupPartyR\aparties\x12!\n" +
+	"\fscore_spread\x18\x06 \x01(\x01R\vscoreSpread\x12!\n" +
+	"\fmatch_window\x18\a \x01(\x01R\vmatchWindow\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\b \x01(\tR\n" +
+	"datacenter\"A\n" +
+	"\x11WatchPartyRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x16\n" +
+	"\x06leader\x18\x02 \x01(\x04R\x06leader\"\xb3\x02\n" +
+	"\n" +
+	"PartyEvent\x122\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1e.matchqueue.v1.PartyEvent.TypeR\x04type\x12\x16\n" +
+	"\x06window\x18\x02 \x01(\x01R\x06window\x12*\n" +
+	"\x05group\x18\x03 \x01(\v2\x14.matchqueue.v1.GroupR\x05group\x12\x1a\n" +
+	"\bcanceled\x18\x04 \x01(\bR\bcanceled\x126\n" +
+	"\twait_time\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\bwaitTime\"Y\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13TYPE_WINDOW_WIDENED\x10\x01\x12\x10\n" +
+	"\fTYPE_MATCHED\x10\x02\x12\x10\n" +
+	"\fTYPE_REMOVED\x10\x032\x8d\x03\n" +
+	"\n" +
+	"MatchQueue\x12N\n" +
+	"\tAddPlayer\x12\x1f.matchqueue.v1.AddPlayerRequest\x1a .matchqueue.v1.AddPlayerResponse\x12W\n" +
+	"\fRemovePlayer\x12\".matchqueue.v1.RemovePlayerRequest\x1a#.matchqueue.v1.RemovePlayerResponse\x12?\n" +
+	"\x05State\x12\x1b.matchqueue.v1.StateRequest\x1a\x19.matchqueue.v1.QueueState\x12H\n" +
+	"\vWatchGroups\x12!.matchqueue.v1.WatchGroupsRequest\x1a\x14.matchqueue.v1.Group0\x01\x12K\n" +
+	"\n" +
+	"WatchParty\x12 .matchqueue.v1.WatchPartyRequest\x1a\x19.matchqueue.v1.PartyEvent0\x01B0Z.github.com/scalcor/matchqueue/rpc/matchqueuepbb\x06proto3"
+
+var (
+	file_matchqueue_proto_rawDescOnce sync.Once
+	file_matchqueue_proto_rawDescData []byte
+)
+
+func file_matchqueue_proto_rawDescGZIP() []byte {
+	file_matchqueue_proto_rawDescOnce.Do(func() {
+		file_matchqueue_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_matchqueue_proto_rawDesc), len(file_matchqueue_proto_rawDesc)))
+	})
+	return file_matchqueue_proto_rawDescData
+}
+
+var file_matchqueue_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_matchqueue_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_matchqueue_proto_goTypes = []any{
+	(PartyEvent_Type)(0),          // 0: matchqueue.v1.PartyEvent.Type
+	(*Player)(nil),                // 1: matchqueue.v1.Player
+	(*Rating)(nil),                // 2: matchqueue.v1.Rating
+	(*AttributeValue)(nil),        // 3: matchqueue.v1.AttributeValue
+	(*StringSet)(nil),             // 4: matchqueue.v1.StringSet
+	(*AddPlayerRequest)(nil),      // 5: matchqueue.v1.AddPlayerRequest
+	(*AddPlayerResponse)(nil),     // 6: matchqueue.v1.AddPlayerResponse
+	(*RemovePlayerRequest)(nil),   // 7: matchqueue.v1.RemovePlayerRequest
+	(*RemovePlayerResponse)(nil),  // 8: matchqueue.v1.RemovePlayerResponse
+	(*StateRequest)(nil),          // 9: matchqueue.v1.StateRequest
+	(*QueueState)(nil),            // 10: matchqueue.v1.QueueState
+	(*WindowState)(nil),           // 11: matchqueue.v1.WindowState
+	(*WatchGroupsRequest)(nil),    // 12: matchqueue.v1.WatchGroupsRequest
+	(*Team)(nil),                  // 13: matchqueue.v1.Team
+	(*GroupParty)(nil),            // 14: matchqueue.v1.GroupParty
+	(*Group)(nil),                 // 15: matchqueue.v1.Group
+	(*WatchPartyRequest)(nil),     // 16: matchqueue.v1.WatchPartyRequest
+	(*PartyEvent)(nil),            // 17: matchqueue.v1.PartyEvent
+	nil,                           // 18: matchqueue.v1.Player.AttributesEntry
+	nil,                           // 19: matchqueue.v1.Player.LatencyEntry
+	(*durationpb.Duration)(nil),   // 20: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 21: google.protobuf.Timestamp
+}
+var file_matchqueue_proto_depIdxs = []int32{
+	18, // 0: matchqueue.v1.Player.attributes:type_name -> matchqueue.v1.Player.AttributesEntry
+	19, // 1: matchqueue.v1.Player.latency:type_name -> matchqueue.v1.Player.LatencyEntry
+	2,  // 2: matchqueue.v1.Player.rating:type_name -> matchqueue.v1.Rating
+	4,  // 3: matchqueue.v1.AttributeValue.set:type_name -> matchqueue.v1.StringSet
+	1,  // 4: matchqueue.v1.AddPlayerRequest.players:type_name -> matchqueue.v1.Player
+	11, // 5: matchqueue.v1.QueueState.windows:type_name -> matchqueue.v1.WindowState
+	1,  // 6: matchqueue.v1.Team.players:type_name -> matchqueue.v1.Player
+	20, // 7: matchqueue.v1.GroupParty.wait_time:type_name -> google.protobuf.Duration
+	13, // 8: matchqueue.v1.Group.teams:type_name -> matchqueue.v1.Team
+	21, // 9: matchqueue.v1.Group.created_at:type_name -> google.protobuf.Timestamp
+	14, // 10: matchqueue.v1.Group.parties:type_name -> matchqueue.v1.GroupParty
+	0,  // 11: matchqueue.v1.PartyEvent.type:type_name -> matchqueue.v1.PartyEvent.Type
+	15, // 12: matchqueue.v1.PartyEvent.group:type_name -> matchqueue.v1.Group
+	20, // 13: matchqueue.v1.PartyEvent.wait_time:type_name -> google.protobuf.Duration
+	3,  // 14: matchqueue.v1.Player.AttributesEntry.value:type_name -> matchqueue.v1.AttributeValue
+	5,  // 15: matchqueue.v1.MatchQueue.AddPlayer:input_type -> matchqueue.v1.AddPlayerRequest
+	7,  // 16: matchqueue.v1.MatchQueue.RemovePlayer:input_type -> matchqueue.v1.RemovePlayerRequest
+	9,  // 17: matchqueue.v1.MatchQueue.State:input_type -> matchqueue.v1.StateRequest
+	12, // 18: matchqueue.v1.MatchQueue.WatchGroups:input_type -> matchqueue.v1.WatchGroupsRequest
+	16, // 19: matchqueue.v1.MatchQueue.WatchParty:input_type -> matchqueue.v1.WatchPartyRequest
+	6,  // 20: matchqueue.v1.MatchQueue.AddPlayer:output_type -> matchqueue.v1.AddPlayerResponse
+	8,  // 21: matchqueue.v1.MatchQueue.RemovePlayer:output_type -> matchqueue.v1.RemovePlayerResponse
+	10, // 22: matchqueue.v1.MatchQueue.State:output_type -> matchqueue.v1.QueueState
+	15, // 23: matchqueue.v1.MatchQueue.WatchGroups:output_type -> matchqueue.v1.Group
+	17, // 24: matchqueue.v1.MatchQueue.WatchParty:output_type -> matchqueue.v1.PartyEvent
+	20, // [20:25] is the sub-list for method output_type
+	15, // [15:20] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_matchqueue_proto_init() }
+func file_matchqueue_proto_init() {
+	if File_matchqueue_proto != nil {
+		return
+	}
+	file_matchqueue_proto_msgTypes[2].OneofWrappers = []any{
+		(*AttributeValue_Text)(nil),
+		(*AttributeValue_Number)(nil),
+		(*AttributeValue_Set)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_matchqueue_proto_rawDesc), len(file_matchqueue_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_matchqueue_proto_goTypes,
+		DependencyIndexes: file_matchqueue_proto_depIdxs,
+		EnumInfos:         file_matchqueue_proto_enumTypes,
+		MessageInfos:      file_matchqueue_proto_msgTypes,
+	}.Build()
+	File_matchqueue_proto = out.File
+	file_matchqueue_proto_goTypes = nil
+	file_matchqueue_proto_depIdxs = nil
+}
@@ -0,0 +1,298 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: matchqueue.proto
+
+package matchqueuepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MatchQueue_AddPlayer_FullMethodName    = "/matchqueue.v1.MatchQueue/AddPlayer"
+	MatchQueue_RemovePlayer_FullMethodName = "/matchqueue.v1.MatchQueue/RemovePlayer"
+	MatchQueue_State_FullMethodName        = "/matchqueue.v1.MatchQueue/State"
+	MatchQueue_WatchGroups_FullMethodName  = "/matchqueue.v1.MatchQueue/WatchGroups"
+	MatchQueue_WatchParty_FullMethodName   = "/matchqueue.v1.MatchQueue/WatchParty"
+)
+
+// MatchQueueClient is the client API for MatchQueue service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MatchQueue serves matching queues.
+// Every request names the queue it is sent to.
+type MatchQueueClient interface {
+	// AddPlayer adds a party to the queue. The first player is the leader of the party.
+	AddPlayer(ctx context.Context, in *AddPlayerRequest, opts ...grpc.CallOption) (*AddPlayerResponse, error)
+	// RemovePlayer removes the party of the leader from the queue.
+	RemovePlayer(ctx context.Context, in *RemovePlayerRequest, opts ...grpc.CallOption) (*RemovePlayerResponse, error)
+	// State returns the queue's current state.
+	State(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*QueueState, error)
+	// WatchGroups streams groups created by the queue.
+	WatchGroups(ctx context.Context, in *WatchGroupsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Group], error)
+	// WatchParty streams events of the party of the leader.
+	// The stream ends when the party is matched or removed.
+	WatchParty(ctx context.Context, in *WatchPartyRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PartyEvent], error)
+}
+
+type matchQueueClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMatchQueueClient(cc grpc.ClientConnInterface) MatchQueueClient {
+	return &matchQueueClient{cc}
+}
+
+func (c *matchQueueClient) AddPlayer(ctx context.Context, in *AddPlayerRequest, opts ...grpc.CallOption) (*AddPlayerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddPlayerResponse)
+	err := c.cc.Invoke(ctx, MatchQueue_AddPlayer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchQueueClient) RemovePlayer(ctx context.Context, in *RemovePlayerRequest, opts ...grpc.CallOption) (*RemovePlayerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemovePlayerResponse)
+	err := c.cc.Invoke(ctx, MatchQueue_RemovePlayer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchQueueClient) State(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*QueueState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueueState)
+	err := c.cc.Invoke(ctx, MatchQueue_State_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchQueueClient) WatchGroups(ctx context.Context, in *WatchGroupsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Group], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MatchQueue_ServiceDesc.Streams[0], MatchQueue_WatchGroups_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchGroupsRequest, Group]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatchQueue_WatchGroupsClient = grpc.ServerStreamingClient[Group]
+
+func (c *matchQueueClient) WatchParty(ctx context.Context, in *WatchPartyRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PartyEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MatchQueue_ServiceDesc.Streams[1], MatchQueue_WatchParty_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchPartyRequest, PartyEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatchQueue_WatchPartyClient = grpc.ServerStreamingClient[PartyEvent]
+
+// MatchQueueServer is the server API for MatchQueue service.
+// All implementations must embed UnimplementedMatchQueueServer
+// for forward compatibility.
+//
+// MatchQueue serves matching queues.
+// Every request names the queue it is sent to.
+type MatchQueueServer interface {
+	// AddPlayer adds a party to the queue. The first player is the leader of the party.
+	AddPlayer(context.Context, *AddPlayerRequest) (*AddPlayerResponse, error)
+	// RemovePlayer removes the party of the leader from the queue.
+	RemovePlayer(context.Context, *RemovePlayerRequest) (*RemovePlayerResponse, error)
+	// State returns the queue's current state.
+	State(context.Context, *StateRequest) (*QueueState, error)
+	// WatchGroups streams groups created by the queue.
+	WatchGroups(*WatchGroupsRequest, grpc.ServerStreamingServer[Group]) error
+	// WatchParty streams events of the party of the leader.
+	// The stream ends when the party is matched or removed.
+	WatchParty(*WatchPartyRequest, grpc.ServerStreamingServer[PartyEvent]) error
+	mustEmbedUnimplementedMatchQueueServer()
+}
+
+// UnimplementedMatchQueueServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMatchQueueServer struct{}
+
+func (UnimplementedMatchQueueServer) AddPlayer(context.Context, *AddPlayerRequest) (*AddPlayerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPlayer not implemented")
+}
+func (UnimplementedMatchQueueServer) RemovePlayer(context.Context, *RemovePlayerRequest) (*RemovePlayerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePlayer not implemented")
+}
+func (UnimplementedMatchQueueServer) State(context.Context, *StateRequest) (*QueueState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method State not implemented")
+}
+func (UnimplementedMatchQueueServer) WatchGroups(*WatchGroupsRequest, grpc.ServerStreamingServer[Group]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchGroups not implemented")
+}
+func (UnimplementedMatchQueueServer) WatchParty(*WatchPartyRequest, grpc.ServerStreamingServer[PartyEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchParty not implemented")
+}
+func (UnimplementedMatchQueueServer) mustEmbedUnimplementedMatchQueueServer() {}
+func (UnimplementedMatchQueueServer) testEmbeddedByValue()                    {}
+
+// UnsafeMatchQueueServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MatchQueueServer will
+// result in compilation errors.
+type UnsafeMatchQueueServer interface {
+	mustEmbedUnimplementedMatchQueueServer()
+}
+
+func RegisterMatchQueueServer(s grpc.ServiceRegistrar, srv MatchQueueServer) {
+	// If the following call pancis, it indicates UnimplementedMatchQueueServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MatchQueue_ServiceDesc, srv)
+}
+
+func _MatchQueue_AddPlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchQueueServer).AddPlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchQueue_AddPlayer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchQueueServer).AddPlayer(ctx, req.(*AddPlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchQueue_RemovePlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchQueueServer).RemovePlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchQueue_RemovePlayer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchQueueServer).RemovePlayer(ctx, req.(*RemovePlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchQueue_State_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchQueueServer).State(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchQueue_State_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchQueueServer).State(ctx, req.(*StateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchQueue_WatchGroups_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchGroupsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MatchQueueServer).WatchGroups(m, &grpc.GenericServerStream[WatchGroupsRequest, Group]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatchQueue_WatchGroupsServer = grpc.ServerStreamingServer[Group]
+
+func _MatchQueue_WatchParty_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPartyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MatchQueueServer).WatchParty(m, &grpc.GenericServerStream[WatchPartyRequest, PartyEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MatchQueue_WatchPartyServer = grpc.ServerStreamingServer[PartyEvent]
+
+// MatchQueue_ServiceDesc is the grpc.ServiceDesc for MatchQueue service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MatchQueue_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "matchqueue.v1.MatchQueue",
+	HandlerType: (*MatchQueueServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddPlayer",
+			Handler:    _MatchQueue_AddPlayer_Handler,
+		},
+		{
+			MethodName: "RemovePlayer",
+			Handler:    _MatchQueue_RemovePlayer_Handler,
+		},
+		{
+			MethodName: "State",
+			Handler:    _MatchQueue_State_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchGroups",
+			Handler:       _MatchQueue_WatchGroups_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchParty",
+			Handler:       _MatchQueue_WatchParty_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "matchqueue.proto",
+}
@@ -0,0 +1,237 @@
+// Package rpc serves matching queues over gRPC.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/scalcor/matchqueue"
+	pb "github.com/scalcor/matchqueue/rpc/matchqueuepb"
+)
+
+// watchBuffer is the number of events buffered for a watching stream.
+// The stream is aborted if the client cannot keep up with the queue.
+const watchBuffer = 64
+
+// Server implements the MatchQueue service over named queues.
+type Server struct {
+	pb.UnimplementedMatchQueueServer
+
+	queues map[string]matchqueue.Queue
+}
+
+var _ pb.MatchQueueServer = new(Server)
+
+// NewServer creates a new server of the queues.
+// The queues are called from multiple goroutines, so they must be safe for concurrent use,
+// e.g. wrapped by matchqueue.Runner. Matching of the queues is driven by their owner.
+func NewServer(queues map[string]matchqueue.Queue) *Server {
+	return &Server{queues: queues}
+}
+
+func (s *Server) queue(name string) (matchqueue.Queue, error) {
+	q, ok := s.queues[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown queue %q", name)
+	}
+	return q, nil
+}
+
+func (s *Server) AddPlayer(ctx context.Context, req *pb.AddPlayerRequest) (*pb.AddPlayerResponse, error) {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.GetPlayers()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no player")
+	}
+	players := make([]*matchqueue.Player, 0, len(req.GetPlayers()))
+	for _, pl := range req.GetPlayers() {
+		if !matchqueue.PlayerID(pl.GetId()).IsValid() {
+			return nil, status.Error(codes.InvalidArgument, "invalid player id")
+		}
+		players = append(players, fromPlayer(pl))
+	}
+
+	if err := q.AddPlayer(players); err != nil {
+		switch {
+		case errors.Is(err, matchqueue.ErrPlayerQueued):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		case errors.Is(err, matchqueue.ErrConstraintViolated):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.AddPlayerResponse{Leader: uint64(players[0].ID)}, nil
+}
+
+func (s *Server) RemovePlayer(ctx context.Context, req *pb.RemovePlayerRequest) (*pb.RemovePlayerResponse, error) {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+
+	leader := matchqueue.PlayerID(req.GetLeader())
+	if !leader.IsValid() {
+		return nil, status.Error(codes.InvalidArgument, "invalid leader id")
+	}
+
+	q.RemovePlayer(leader, true)
+
+	return &pb.RemovePlayerResponse{}, nil
+}
+
+func (s *Server) State(ctx context.Context, req *pb.StateRequest) (*pb.QueueState, error) {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return nil, err
+	}
+
+	return toState(q.State()), nil
+}
+
+func (s *Server) WatchGroups(req *pb.WatchGroupsRequest, stream pb.MatchQueue_WatchGroupsServer) error {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return err
+	}
+
+	events, unsubscribe := watch(q, func(noti matchqueue.Notification) (*pb.Group, bool) {
+		if noti.Message != matchqueue.NotifyGroupCreated {
+			return nil, false
+		}
+		return toGroup(noti.Data["group"].(*matchqueue.Group)), true
+	})
+	defer unsubscribe()
+
+	// let the client know that events are being watched
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case g, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "too slow to receive groups")
+			}
+			if err := stream.Send(g); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *Server) WatchParty(req *pb.WatchPartyRequest, stream pb.MatchQueue_WatchPartyServer) error {
+	q, err := s.queue(req.GetQueue())
+	if err != nil {
+		return err
+	}
+
+	leader := matchqueue.PlayerID(req.GetLeader())
+	if !leader.IsValid() {
+		return status.Error(codes.InvalidArgument, "invalid leader id")
+	}
+
+	events, unsubscribe := watch(q, func(noti matchqueue.Notification) (*pb.PartyEvent, bool) {
+		return toPartyEvent(leader, noti)
+	})
+	defer unsubscribe()
+
+	// let the client know that events are being watched
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "too slow to receive party events")
+			}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+			if ev.GetType() != pb.PartyEvent_TYPE_WINDOW_WIDENED {
+				// the party left the queue
+				return nil
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// watch subscribes the queue and delivers converted notifications through the returned channel.
+// Notifications are delivered while the queue is processing, so they are never blocked;
+// the channel is closed when its buffer overflows.
+func watch[T any](q matchqueue.Queue, convert func(matchqueue.Notification) (T, bool)) (<-chan T, func()) {
+	ch := make(chan T, watchBuffer)
+	closed := false
+
+	unsubscribe := q.Subscribe(func(noti matchqueue.Notification) {
+		if closed {
+			return
+		}
+		v, ok := convert(noti)
+		if !ok {
+			return
+		}
+
+		select {
+		case ch <- v:
+		default:
+			closed = true
+			close(ch)
+		}
+	})
+	return ch, unsubscribe
+}
+
+// toPartyEvent converts the notification into an event of the party of the leader.
+func toPartyEvent(leader matchqueue.PlayerID, noti matchqueue.Notification) (*pb.PartyEvent, bool) {
+	switch noti.Message {
+	case matchqueue.NotifyPartyWindowWidened:
+		if noti.Data["leader"] != leader {
+			return nil, false
+		}
+		return &pb.PartyEvent{
+			Type:   pb.PartyEvent_TYPE_WINDOW_WIDENED,
+			Window: noti.Data["new"].(float64),
+		}, true
+
+	case matchqueue.NotifyGroupCreated:
+		g := noti.Data["group"].(*matchqueue.Group)
+		idx := slices.IndexFunc(g.Parties, func(gp matchqueue.GroupParty) bool { return gp.Leader == leader })
+		if idx < 0 {
+			return nil, false
+		}
+		return &pb.PartyEvent{
+			Type:     pb.PartyEvent_TYPE_MATCHED,
+			Group:    toGroup(g),
+			WaitTime: durationpb.New(g.Parties[idx].WaitTime),
+		}, true
+
+	case matchqueue.NotifyPartyRemoved:
+		if noti.Data["leader"] != leader {
+			return nil, false
+		}
+		return &pb.PartyEvent{
+			Type:     pb.PartyEvent_TYPE_REMOVED,
+			Canceled: noti.Data["canceled"].(bool),
+			WaitTime: durationpb.New(noti.Data["wait_time"].(time.Duration)),
+		}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,148 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/scalcor/matchqueue"
+	pb "github.com/scalcor/matchqueue/rpc/matchqueuepb"
+)
+
+func newTestClient(t *testing.T, queues map[string]matchqueue.Queue) pb.MatchQueueClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterMatchQueueServer(srv, NewServer(queues))
+	go srv.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+	return pb.NewMatchQueueClient(conn)
+}
+
+func newDuelRunner() *matchqueue.Runner {
+	conf := matchqueue.DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.NumRoundToCreateGroup = 1
+	return matchqueue.NewRunner(matchqueue.New(conf), time.Hour)
+}
+
+func Test_Server_AddRemoveState(t *testing.T) {
+	r := newDuelRunner()
+	client := newTestClient(t, map[string]matchqueue.Queue{"duel": r})
+	ctx := context.Background()
+
+	resp, err := client.AddPlayer(ctx, &pb.AddPlayerRequest{Queue: "duel", Players: []*pb.Player{{Id: 1, Score: 25}, {Id: 2, Score: 30}}})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), resp.GetLeader())
+
+	_, err = r.ProcMatching()
+	require.NoError(t, err)
+
+	state, err := client.State(ctx, &pb.StateRequest{Queue: "duel"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), state.GetRound())
+	assert.Equal(t, int64(2), state.GetPlayerQueued())
+	assert.Len(t, state.GetWindows(), len(matchqueue.DefaultConfig().StatWindowSec))
+
+	_, err = client.RemovePlayer(ctx, &pb.RemovePlayerRequest{Queue: "duel", Leader: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, r.State().PlayerCanceled)
+
+	// errors
+	_, err = client.AddPlayer(ctx, &pb.AddPlayerRequest{Queue: "unknown", Players: []*pb.Player{{Id: 1}}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.AddPlayer(ctx, &pb.AddPlayerRequest{Queue: "duel", Players: []*pb.Player{{Id: 3}, {Id: 3}}})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = client.AddPlayer(ctx, &pb.AddPlayerRequest{Queue: "duel"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.AddPlayer(ctx, &pb.AddPlayerRequest{Queue: "duel", Players: []*pb.Player{{Id: 0}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.RemovePlayer(ctx, &pb.RemovePlayerRequest{Queue: "duel"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_Server_Watch(t *testing.T) {
+	r := newDuelRunner()
+	client := newTestClient(t, map[string]matchqueue.Queue{"duel": r})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	groups, err := client.WatchGroups(ctx, &pb.WatchGroupsRequest{Queue: "duel"})
+	require.NoError(t, err)
+	party, err := client.WatchParty(ctx, &pb.WatchPartyRequest{Queue: "duel", Leader: 1})
+	require.NoError(t, err)
+	canceled, err := client.WatchParty(ctx, &pb.WatchPartyRequest{Queue: "duel", Leader: 3})
+	require.NoError(t, err)
+
+	// headers are sent once the streams watch the queue
+	for _, stream := range []grpc.ClientStream{groups, party, canceled} {
+		_, err := stream.Header()
+		require.NoError(t, err)
+	}
+
+	r.AddPlayer([]*matchqueue.Player{{ID: 1, Score: 25}})
+	r.AddPlayer([]*matchqueue.Player{{ID: 2, Score: 26}})
+	r.AddPlayer([]*matchqueue.Player{{ID: 3, Score: 90}})
+
+	created, err := r.ProcMatching()
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+
+	g, err := groups.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(created[0].ID), g.GetId())
+	require.Len(t, g.GetTeams(), 2)
+	assert.Len(t, g.GetTeams()[0].GetPlayers(), 1)
+	assert.InDelta(t, 1.0, g.GetTeams()[0].GetWinProbability()+g.GetTeams()[1].GetWinProbability(), 1e-9)
+	assert.Len(t, g.GetParties(), 2)
+	assert.InDelta(t, 1.0, g.GetScoreSpread(), 1e-9)
+
+	ev, err := party.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.PartyEvent_TYPE_MATCHED, ev.GetType())
+	assert.Equal(t, g.GetId(), ev.GetGroup().GetId())
+	assert.NotNil(t, ev.GetWaitTime())
+
+	// the stream of a matched party ends
+	_, err = party.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	r.RemovePlayer(3, true)
+
+	ev, err = canceled.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.PartyEvent_TYPE_REMOVED, ev.GetType())
+	assert.True(t, ev.GetCanceled())
+
+	_, err = canceled.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	// unknown queue
+	stream, err := client.WatchGroups(ctx, &pb.WatchGroupsRequest{Queue: "unknown"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
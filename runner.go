@@ -0,0 +1,213 @@
+package matchqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const runnerGroupBuffer = 64
+
+type runnerStatus int
+
+const (
+	runnerIdle runnerStatus = iota
+	runnerRunning
+	runnerStopped
+)
+
+// Runner wraps a Queue so that it can be shared by multiple goroutines.
+// Every method of the Queue is serialized by a mutex, and once started,
+// the runner calls ProcMatching on every interval in its own goroutine
+// and delivers the created groups through the channel returned by Groups.
+type Runner struct {
+	mu sync.Mutex
+	q  Queue
+
+	interval time.Duration
+	groups   chan *Group
+	onError  func(error)
+
+	// loop control
+	status runnerStatus
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+var _ Queue = new(Runner)
+
+// NewRunner creates a new runner of the given queue.
+// The queue must not be used directly once it is wrapped.
+func NewRunner(q Queue, interval time.Duration) *Runner {
+	return &Runner{
+		q:        q,
+		interval: interval,
+		groups:   make(chan *Group, runnerGroupBuffer),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// implementation of Queue
+func (r *Runner) AddPlayer(players []*Player) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.q.AddPlayer(players)
+}
+
+func (r *Runner) RemovePlayer(id PlayerID, updateState bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.q.RemovePlayer(id, updateState)
+}
+
+func (r *Runner) FindPlayer(id PlayerID) (PartyInfo, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.q.FindPlayer(id)
+}
+
+func (r *Runner) State() State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.q.State()
+}
+
+// ProcMatching does a matching process immediately.
+// Created groups are returned to the caller and not delivered to Groups.
+func (r *Runner) ProcMatching() ([]*Group, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.q.ProcMatching()
+}
+
+// Subscribe registers a function which receives notifications of the queue.
+// The function is called while the runner is locked, so it must not call the runner's methods.
+func (r *Runner) Subscribe(fn func(Notification)) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unsubscribe := r.q.Subscribe(fn)
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		unsubscribe()
+	}
+}
+
+func (r *Runner) Snapshot() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.q.Snapshot()
+}
+
+func (r *Runner) Restore(data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.q.Restore(data)
+}
+
+func (r *Runner) UpdateConfig(conf *Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.q.UpdateConfig(conf)
+}
+
+// OnError sets a function which receives errors of the matching rounds of the loop, except ErrNotEnoughPlayer.
+// The function is called while the runner is locked, so it must not call the runner's methods.
+func (r *Runner) OnError(fn func(error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onError = fn
+}
+
+// Groups returns the channel which delivers groups created by the matching loop.
+// The channel is closed when the runner stops.
+func (r *Runner) Groups() <-chan *Group {
+	return r.groups
+}
+
+// Start starts the matching loop.
+// A runner can be started only once.
+func (r *Runner) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status != runnerIdle {
+		return ErrRunnerStarted
+	}
+	r.status = runnerRunning
+
+	go r.loop()
+	return nil
+}
+
+// Stop stops the matching loop and waits until it exits or ctx is done.
+// The matching round in progress, if any, is completed before the loop exits,
+// but its groups which are not received from Groups by then are dropped.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	if r.status != runnerRunning {
+		r.mu.Unlock()
+		return ErrRunnerNotRunning
+	}
+	r.status = runnerStopped
+	close(r.stop)
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// procMatchingLoop does a matching process of the loop and reports its error to the error handler.
+func (r *Runner) procMatchingLoop() []*Group {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	groups, err := r.q.ProcMatching()
+	if err != nil && !errors.Is(err, ErrNotEnoughPlayer) && r.onError != nil {
+		r.onError(err)
+	}
+	return groups
+}
+
+func (r *Runner) loop() {
+	defer close(r.done)
+	defer close(r.groups)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+
+		groups := r.procMatchingLoop()
+
+		for _, g := range groups {
+			select {
+			case r.groups <- g:
+			case <-r.stop:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package matchqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Runner(t *testing.T) {
+	conf := DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.NumRoundToCreateGroup = 1
+
+	r := NewRunner(New(conf), time.Millisecond)
+
+	// add players from many goroutines
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(id PlayerID) {
+			defer wg.Done()
+			r.AddPlayer([]*Player{{ID: id, Score: 25.0}})
+		}(PlayerID(i))
+	}
+	wg.Wait()
+
+	require.NoError(t, r.Start())
+	assert.ErrorIs(t, r.Start(), ErrRunnerStarted)
+
+	matched := map[PlayerID]struct{}{}
+	timeout := time.After(5 * time.Second)
+	for len(matched) < 20 {
+		select {
+		case g := <-r.Groups():
+			for _, team := range g.Players {
+				for _, pl := range team {
+					matched[pl.ID] = struct{}{}
+				}
+			}
+		case <-timeout:
+			t.Fatalf("only %d players matched", len(matched))
+		}
+	}
+
+	require.NoError(t, r.Stop(context.Background()))
+	assert.ErrorIs(t, r.Stop(context.Background()), ErrRunnerNotRunning)
+
+	// groups channel is closed after stop
+	_, ok := <-r.Groups()
+	assert.False(t, ok)
+	assert.Equal(t, 10, r.State().GroupCreated)
+}
+
+// failingQueue is a queue whose matching always fails.
+type failingQueue struct {
+	Queue
+	err error
+}
+
+func (q *failingQueue) ProcMatching() ([]*Group, error) {
+	return nil, q.err
+}
+
+func Test_Runner_OnError(t *testing.T) {
+	boom := errors.New("boom")
+	r := NewRunner(&failingQueue{Queue: New(DefaultConfig()), err: boom}, time.Millisecond)
+
+	errs := make(chan error, 1)
+	r.OnError(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	require.NoError(t, r.Start())
+	defer r.Stop(context.Background())
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, boom)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error reported")
+	}
+}
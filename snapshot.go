@@ -0,0 +1,129 @@
+package matchqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// snapshotVersion is the version of the snapshot format.
+// It must be increased when the format changes incompatibly.
+const snapshotVersion = 1
+
+type (
+	snapshot struct {
+		Version           int              `json:"version"`
+		MatchWindow       float64          `json:"match_window"`
+		RoundGroupCreated uint64           `json:"round_group_created"`
+		IDPool            GroupID          `json:"id_pool"`
+		State             State            `json:"state"`
+		WaitSketch        waitSketch       `json:"wait_sketch,omitempty"` // wait times of all matched players
+		Windows           []windowSnapshot `json:"windows,omitempty"`
+		Parties           []partySnapshot  `json:"parties"` // sorted by the join order
+	}
+
+	windowSnapshot struct {
+		PeriodSec int            `json:"period_sec"`
+		Slots     []slotSnapshot `json:"slots"`
+	}
+
+	slotSnapshot struct {
+		Start    time.Time  `json:"start"`
+		Matched  int        `json:"matched"`
+		Canceled int        `json:"canceled"`
+		Groups   int        `json:"groups"`
+		Waits    waitSketch `json:"waits"`
+	}
+
+	partySnapshot struct {
+		Players   []*Player `json:"players"`
+		CreatedAt time.Time `json:"created_at"`
+		WaitCnt   int       `json:"wait_cnt"`
+	}
+)
+
+// Snapshot serializes the queue's internal state.
+func (q *queue) Snapshot() ([]byte, error) {
+	s := snapshot{
+		Version:           snapshotVersion,
+		MatchWindow:       q.matchWindow,
+		RoundGroupCreated: q.roundGroupCreated,
+		IDPool:            q.idPool,
+		State:             *q.state,
+	}
+	if q.stats != nil {
+		s.WaitSketch = q.stats.waits
+		for _, w := range q.stats.windows {
+			ws := windowSnapshot{PeriodSec: int(w.period / time.Second)}
+			for _, slot := range w.slots {
+				if !slot.start.IsZero() {
+					ws.Slots = append(ws.Slots, slotSnapshot{
+						Start: slot.start, Matched: slot.matched, Canceled: slot.canceled, Groups: slot.groups, Waits: slot.waits,
+					})
+				}
+			}
+			s.Windows = append(s.Windows, ws)
+		}
+	}
+
+	for _, p := range q.parties {
+		s.Parties = append(s.Parties, partySnapshot{Players: p.players, CreatedAt: p.createdAt, WaitCnt: p.waitCnt})
+	}
+
+	return json.Marshal(s)
+}
+
+// Restore replaces the queue's internal state with the snapshot.
+// Matching factors of parties are calculated again with the queue's configuration.
+func (q *queue) Restore(data []byte) error {
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("%w: %d", ErrSnapshotVersion, s.Version)
+	}
+
+	q.matchWindow = s.MatchWindow
+	q.roundGroupCreated = s.RoundGroupCreated
+	q.idPool = s.IDPool
+	*q.state = s.State
+	q.stats = newQueueStats(q.config.StatWindowSec)
+	if s.WaitSketch != nil {
+		q.stats.waits = s.WaitSketch
+	}
+	for _, ws := range s.Windows {
+		// restore windows of the same period only
+		for _, w := range q.stats.windows {
+			if w.period != time.Duration(ws.PeriodSec)*time.Second {
+				continue
+			}
+			for _, ss := range ws.Slots {
+				slot := w.slot(ss.Start)
+				slot.matched, slot.canceled, slot.groups = ss.Matched, ss.Canceled, ss.Groups
+				slot.waits.merge(ss.Waits)
+			}
+		}
+	}
+
+	q.parties = nil
+	q.partiesSorted = nil
+	q.members = map[PlayerID]*party{}
+	q.playerCnt = 0
+
+	for _, ps := range s.Parties {
+		p := newParty(q, ps.Players)
+		if p == nil {
+			continue
+		}
+
+		p.createdAt = ps.CreatedAt
+		p.waitCnt = ps.WaitCnt
+		p.AdjustMatchingFactor(0.0)
+		p.UpdateWindowSize(q.matchWindow)
+
+		q.addParty(p)
+	}
+
+	return nil
+}
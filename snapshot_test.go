@@ -0,0 +1,74 @@
+package matchqueue
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_queue_Snapshot(t *testing.T) {
+	conf := DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	src := New(conf, WithClock(clock)).(*queue)
+	for i, score := range []float64{10.0, 12.0, 30.0, 50.0, 90.0} {
+		src.AddPlayer([]*Player{{ID: PlayerID(i + 1), Score: score}})
+	}
+	for range 3 {
+		_, err := src.ProcMatching()
+		require.NoError(t, err)
+	}
+
+	data, err := src.Snapshot()
+	require.NoError(t, err)
+
+	dst := New(conf, WithClock(clock)).(*queue)
+	require.NoError(t, dst.Restore(data))
+
+	assert.Equal(t, src.matchWindow, dst.matchWindow)
+	assert.Equal(t, src.roundGroupCreated, dst.roundGroupCreated)
+	assert.Equal(t, src.idPool, dst.idPool)
+	assert.Equal(t, src.playerCnt, dst.playerCnt)
+	assert.Equal(t, src.State(), dst.State())
+	require.Equal(t, len(src.parties), len(dst.parties))
+	for i, p := range src.parties {
+		got := dst.parties[i]
+		assert.Equal(t, p.id, got.id)
+		assert.True(t, p.createdAt.Equal(got.createdAt))
+		assert.Equal(t, p.waitCnt, got.waitCnt)
+		assert.Equal(t, p.avgScoreMod, got.avgScoreMod)
+		assert.Equal(t, p.matchWindow, got.matchWindow)
+		assert.Same(t, got, dst.partiesSorted[indexOfParty(src.partiesSorted, p)])
+	}
+
+	// both queues continue matching in the same way
+	for range 5 {
+		want, err := src.ProcMatching()
+		require.NoError(t, err)
+		got, err := dst.ProcMatching()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func Test_queue_Restore_version(t *testing.T) {
+	data, _ := json.Marshal(snapshot{Version: snapshotVersion + 1})
+
+	q := New(DefaultConfig())
+	assert.ErrorIs(t, q.Restore(data), ErrSnapshotVersion)
+}
+
+func indexOfParty(parties []*party, p *party) int {
+	for i, t := range parties {
+		if t == p {
+			return i
+		}
+	}
+	return -1
+}
@@ -16,6 +16,26 @@ type State struct {
 	CanceledWaitTimeAvg uint64 // average of wait time (second) of all canceled players
 	CanceledWaitTimeMax uint64 // maximum wait time (second) among all canceled players
 	PlayerCanceled      int    // number of canceled players
+
+	// distribution of wait time of all matched players; calculated from a sketch, so they are approximate
+	WaitTimeP50 uint64 // 50th percentile of wait time (second)
+	WaitTimeP90 uint64 // 90th percentile of wait time (second)
+	WaitTimeP99 uint64 // 99th percentile of wait time (second)
+
+	// statistics of recent periods, in the order of Config.StatWindowSec
+	Windows []WindowState
+}
+
+// WindowState is statistics of players who left the queue during the recent period.
+type WindowState struct {
+	Period         uint64  // length of the period (second)
+	GroupCreated   int     // number of created groups
+	PlayerMatched  int     // number of matched players
+	PlayerCanceled int     // number of canceled players
+	MatchRate      float64 // ratio of matched players among matched and canceled players
+	WaitTimeP50    uint64  // 50th percentile of wait time (second) of matched players
+	WaitTimeP90    uint64  // 90th percentile of wait time (second) of matched players
+	WaitTimeP99    uint64  // 99th percentile of wait time (second) of matched players
 }
 
 func (s *State) AddMatched(waitTime uint64, cnt int) {
@@ -0,0 +1,213 @@
+package matchqueue
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// growth rate of bucket bounds of waitSketch.
+	// A quantile from the sketch has a relative error less than (sketchGamma-1)/2.
+	sketchGamma = 1.05
+
+	// number of slots of a sliding window
+	windowSlots = 60
+)
+
+var logSketchGamma = math.Log(sketchGamma)
+
+// waitSketch is a sparse histogram of wait times whose bucket bounds grow exponentially,
+// so its size is bounded by the logarithm of the longest wait time.
+type waitSketch map[int]uint64
+
+func sketchIndex(sec float64) int {
+	if sec < 1.0 {
+		return 0
+	}
+	return 1 + int(math.Log(sec)/logSketchGamma)
+}
+
+// sketchValue returns the representative value of the bucket.
+func sketchValue(idx int) float64 {
+	if idx == 0 {
+		return 0.0
+	}
+	lower := math.Pow(sketchGamma, float64(idx-1))
+	return lower * (1.0 + sketchGamma) / 2.0
+}
+
+func (s waitSketch) add(waitTime time.Duration, cnt int) {
+	s[sketchIndex(waitTime.Seconds())] += uint64(cnt)
+}
+
+func (s waitSketch) merge(t waitSketch) {
+	for idx, cnt := range t {
+		s[idx] += cnt
+	}
+}
+
+// quantiles returns the wait times (second) at the given quantiles.
+// qs must be sorted in ascending order.
+func (s waitSketch) quantiles(qs ...float64) []uint64 {
+	results := make([]uint64, len(qs))
+
+	total := uint64(0)
+	indices := make([]int, 0, len(s))
+	for idx, cnt := range s {
+		total += cnt
+		indices = append(indices, idx)
+	}
+	if total == 0 {
+		return results
+	}
+	slices.Sort(indices)
+
+	i, cumulative := 0, uint64(0)
+	for _, idx := range indices {
+		cumulative += s[idx]
+		for ; i < len(qs) && float64(cumulative) >= math.Ceil(qs[i]*float64(total)); i++ {
+			results[i] = uint64(math.Round(sketchValue(idx)))
+		}
+	}
+	for ; i < len(qs); i++ {
+		results[i] = uint64(math.Round(sketchValue(indices[len(indices)-1])))
+	}
+	return results
+}
+
+// slidingWindow accumulates statistics of the recent period.
+// The period is divided into slots, and the oldest slot is reused as time passes.
+type slidingWindow struct {
+	period time.Duration
+	slots  [windowSlots]windowSlot
+}
+
+type windowSlot struct {
+	start    time.Time
+	matched  int
+	canceled int
+	groups   int
+	waits    waitSketch
+}
+
+func (w *slidingWindow) slotDuration() time.Duration {
+	return max(w.period/windowSlots, 1)
+}
+
+// slot returns the slot of the time, resetting it if it holds an older period.
+func (w *slidingWindow) slot(t time.Time) *windowSlot {
+	d := w.slotDuration()
+	start := t.Truncate(d)
+	s := &w.slots[(start.UnixNano()/int64(d))%windowSlots]
+	if !s.start.Equal(start) {
+		*s = windowSlot{start: start, waits: waitSketch{}}
+	}
+	return s
+}
+
+// state returns the statistics of the period until now.
+func (w *slidingWindow) state(now time.Time) WindowState {
+	ws, waits := w.collect(now)
+	return ws.finish(waits)
+}
+
+// collect sums up the slots of the period until now.
+// The rate and quantiles of the returned state are not calculated; see WindowState.finish.
+func (w *slidingWindow) collect(now time.Time) (ws WindowState, waits waitSketch) {
+	ws.Period = uint64(w.period / time.Second)
+
+	from := now.Add(-w.period)
+	waits = waitSketch{}
+	for i := range w.slots {
+		s := &w.slots[i]
+		if s.start.IsZero() || !s.start.After(from) || s.start.After(now) {
+			continue
+		}
+		ws.GroupCreated += s.groups
+		ws.PlayerMatched += s.matched
+		ws.PlayerCanceled += s.canceled
+		waits.merge(s.waits)
+	}
+	return ws, waits
+}
+
+// finish calculates the match rate and quantiles of wait times of the state.
+func (ws WindowState) finish(waits waitSketch) WindowState {
+	if left := ws.PlayerMatched + ws.PlayerCanceled; left > 0 {
+		ws.MatchRate = float64(ws.PlayerMatched) / float64(left)
+	}
+	p := waits.quantiles(0.5, 0.9, 0.99)
+	ws.WaitTimeP50, ws.WaitTimeP90, ws.WaitTimeP99 = p[0], p[1], p[2]
+
+	return ws
+}
+
+// queueStats holds statistics which are not kept in State.
+// Its methods do nothing on nil.
+type queueStats struct {
+	waits   waitSketch // wait times of all matched players
+	windows []*slidingWindow
+}
+
+func newQueueStats(windowSec []int) *queueStats {
+	s := &queueStats{waits: waitSketch{}}
+	s.setWindows(windowSec)
+	return s
+}
+
+// setWindows replaces sliding windows of the statistics.
+func (s *queueStats) setWindows(windowSec []int) {
+	s.windows = nil
+	for _, sec := range windowSec {
+		s.windows = append(s.windows, &slidingWindow{period: time.Duration(sec) * time.Second})
+	}
+}
+
+func (s *queueStats) addGroup(now time.Time) {
+	if s == nil {
+		return
+	}
+
+	for _, w := range s.windows {
+		w.slot(now).groups++
+	}
+}
+
+func (s *queueStats) addMatched(now time.Time, waitTime time.Duration, cnt int) {
+	if s == nil {
+		return
+	}
+
+	s.waits.add(waitTime, cnt)
+	for _, w := range s.windows {
+		slot := w.slot(now)
+		slot.matched += cnt
+		slot.waits.add(waitTime, cnt)
+	}
+}
+
+func (s *queueStats) addCanceled(now time.Time, cnt int) {
+	if s == nil {
+		return
+	}
+
+	for _, w := range s.windows {
+		w.slot(now).canceled += cnt
+	}
+}
+
+// fill sets the statistics to the state.
+func (s *queueStats) fill(state *State, now time.Time) {
+	if s == nil {
+		return
+	}
+
+	p := s.waits.quantiles(0.5, 0.9, 0.99)
+	state.WaitTimeP50, state.WaitTimeP90, state.WaitTimeP99 = p[0], p[1], p[2]
+
+	state.Windows = nil
+	for _, w := range s.windows {
+		state.Windows = append(state.Windows, w.state(now))
+	}
+}
@@ -0,0 +1,66 @@
+package matchqueue
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_waitSketch_quantiles(t *testing.T) {
+	s := waitSketch{}
+	for sec := 1; sec <= 1000; sec++ {
+		s.add(time.Duration(sec)*time.Second, 1)
+	}
+
+	got := s.quantiles(0.5, 0.9, 0.99)
+	for i, want := range []float64{500, 900, 990} {
+		assert.InDelta(t, want, float64(got[i]), want*(sketchGamma-1.0)/2.0+1.0)
+	}
+
+	// size of the sketch is bounded by the logarithm of the longest wait time
+	assert.LessOrEqual(t, len(s), int(math.Log(1000)/math.Log(sketchGamma))+2)
+
+	assert.Equal(t, []uint64{0, 0}, waitSketch{}.quantiles(0.5, 0.9))
+}
+
+func Test_queue_State_windows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	conf := DefaultConfig()
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.StatWindowSec = []int{60, 600}
+
+	q := New(conf, WithClock(clock)).(*queue)
+
+	// 2 players are matched after waiting 30 seconds
+	q.AddPlayer([]*Player{{ID: 1, Score: 25.0}})
+	q.AddPlayer([]*Player{{ID: 2, Score: 25.0}})
+	clock.Advance(30 * time.Second)
+	_, err := q.ProcCreate()
+	assert.NoError(t, err)
+
+	// 2 players are canceled
+	q.AddPlayer([]*Player{{ID: 3, Score: 25.0}, {ID: 4, Score: 25.0}})
+	clock.Advance(10 * time.Second)
+	q.RemovePlayer(3, true)
+
+	state := q.State()
+	assert.Equal(t, uint64(30), state.WaitTimeP50)
+	assert.Equal(t, uint64(30), state.WaitTimeP99)
+	assert.Equal(t, []WindowState{
+		{Period: 60, GroupCreated: 1, PlayerMatched: 2, PlayerCanceled: 2, MatchRate: 0.5, WaitTimeP50: 30, WaitTimeP90: 30, WaitTimeP99: 30},
+		{Period: 600, GroupCreated: 1, PlayerMatched: 2, PlayerCanceled: 2, MatchRate: 0.5, WaitTimeP50: 30, WaitTimeP90: 30, WaitTimeP99: 30},
+	}, state.Windows)
+
+	// the first window forgets them after its period
+	clock.Advance(2 * time.Minute)
+
+	state = q.State()
+	assert.Equal(t, uint64(30), state.WaitTimeP50)
+	assert.Equal(t, WindowState{Period: 60}, state.Windows[0])
+	assert.Equal(t, 2, state.Windows[1].PlayerMatched)
+}
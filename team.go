@@ -0,0 +1,308 @@
+package matchqueue
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// maximum number of nodes to visit while searching team arrangements
+const arrangeSearchLimit = 1 << 16
+
+// arrangeTeams assigns candidates to the teams of the queue's team layout.
+// It searches the arrangement which minimizes the difference of average scores between teams,
+// keeping the players of a party in the same team and satisfying role rules of the teams.
+// It returns nil if the candidates cannot satisfy the layout.
+// If role rules are configured, the assigned role of each player is also returned.
+func (q *queue) arrangeTeams(candidates []*party) ([][]*party, map[PlayerID]string) {
+	layouts, balanced := q.config.teamLayouts()
+	teamRules := q.config.teamRoleRules(layouts)
+
+	// sort candidates by number of players, descending
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].players) > len(candidates[j].players)
+	})
+
+	total := 0
+	for _, cand := range candidates {
+		total += len(cand.players)
+	}
+
+	if balanced {
+		// number of players of each team cannot differ more than 1
+		lower := total / len(layouts)
+		upper := (total + len(layouts) - 1) / len(layouts)
+
+		balancedLayouts := make([]TeamLayout, len(layouts))
+		for i := range balancedLayouts {
+			balancedLayouts[i] = TeamLayout{MinSize: lower, MaxSize: upper, Roles: layouts[i].Roles}
+		}
+		layouts = balancedLayouts
+	}
+
+	s := &arrangeSearch{
+		layouts:    layouts,
+		candidates: candidates,
+		assigned:   make([]int, len(candidates)),
+		counts:     make([]int, len(layouts)),
+		sums:       make([]float64, len(layouts)),
+		remaining:  total,
+		bestCost:   math.Inf(1),
+	}
+	if teamRules != nil {
+		s.rules = teamRules
+		s.assigner = newRoleAssigner(nil)
+	}
+	s.search(0)
+
+	if s.best == nil {
+		return nil, nil
+	}
+
+	teams := make([][]*party, len(layouts))
+	for i, team := range s.best {
+		teams[team] = append(teams[team], candidates[i])
+	}
+
+	if teamRules == nil {
+		return teams, nil
+	}
+
+	roles := map[PlayerID]string{}
+	for i, team := range teams {
+		players := partyPlayers(team)
+		s.assigner.rules = teamRules[i]
+		assigned, _, _ := s.assigner.assign(players)
+		for j, pl := range players {
+			if assigned[j] != restRole {
+				roles[pl.ID] = assigned[j]
+			}
+		}
+	}
+	return teams, roles
+}
+
+// partyPlayers returns all players of the parties.
+func partyPlayers(parties []*party) []*Player {
+	var players []*Player
+	for _, p := range parties {
+		players = append(players, p.players...)
+	}
+	return players
+}
+
+// arrangeSearch is a depth-first search of team arrangements.
+type arrangeSearch struct {
+	layouts    []TeamLayout
+	candidates []*party
+
+	// current arrangement
+	assigned  []int     // team index of each candidate
+	counts    []int     // number of players of each team
+	sums      []float64 // sum of scores of each team
+	remaining int       // number of players not assigned yet
+
+	// role rules of each team; nil if roles are not configured
+	rules    [][]RoleRule
+	assigner *roleAssigner
+
+	// best arrangement
+	best     []int
+	bestCost float64
+
+	visited int
+}
+
+func (s *arrangeSearch) search(idx int) {
+	if s.visited >= arrangeSearchLimit || s.bestCost == 0.0 {
+		return
+	}
+	s.visited++
+
+	// remaining players must be able to fill all teams up to their minimum size
+	required := 0
+	for i, l := range s.layouts {
+		required += max(l.MinSize-s.counts[i], 0)
+	}
+	if required > s.remaining {
+		return
+	}
+
+	if idx == len(s.candidates) {
+		if !s.rolesFit(true) {
+			return
+		}
+		if cost := s.cost(); cost < s.bestCost {
+			s.bestCost = cost
+			s.best = append(s.best[:0], s.assigned...)
+		}
+		return
+	}
+
+	cand := s.candidates[idx]
+	size := len(cand.players)
+	sum := 0.0
+	for _, pl := range cand.players {
+		sum += pl.skill()
+	}
+
+	for _, team := range s.teamOrder() {
+		l := s.layouts[team]
+		if l.MaxSize > 0 && s.counts[team]+size > l.MaxSize {
+			continue
+		}
+		if s.counts[team] == 0 && s.hasSameEmptyTeam(team) {
+			// it is the same as assigning to the preceding empty team
+			continue
+		}
+
+		s.assigned[idx] = team
+		s.counts[team] += size
+		s.sums[team] += sum
+		s.remaining -= size
+
+		if s.rules == nil || s.teamRolesFit(team, idx+1, false) {
+			s.search(idx + 1)
+		}
+
+		s.assigned[idx] = 0
+		s.counts[team] -= size
+		s.sums[team] -= sum
+		s.remaining += size
+	}
+}
+
+// rolesFit checks if roles can be assigned to players of every team.
+// If complete is true, the minimums of the role rules must be satisfied.
+func (s *arrangeSearch) rolesFit(complete bool) bool {
+	if s.rules == nil {
+		return true
+	}
+	for team := range s.layouts {
+		if !s.teamRolesFit(team, len(s.candidates), complete) {
+			return false
+		}
+	}
+	return true
+}
+
+// teamRolesFit checks if roles can be assigned to players of the team among the first n candidates.
+// If complete is false, the minimums of the role rules may be satisfied by players added later.
+func (s *arrangeSearch) teamRolesFit(team, n int, complete bool) bool {
+	var players []*Player
+	for i := range n {
+		if s.assigned[i] == team {
+			players = append(players, s.candidates[i].players...)
+		}
+	}
+
+	free := 0
+	if !complete {
+		free = math.MaxInt
+		if l := s.layouts[team]; l.MaxSize > 0 {
+			free = l.MaxSize - s.counts[team]
+		}
+	}
+
+	s.assigner.rules = s.rules[team]
+	return s.assigner.fits(players, free)
+}
+
+// teamOrder returns team indices sorted by their occupancy, ascending.
+// Trying less filled teams first makes the first arrangement found reasonably balanced.
+func (s *arrangeSearch) teamOrder() []int {
+	fill := func(i int) float64 {
+		if s.layouts[i].MaxSize > 0 {
+			return float64(s.counts[i]) / float64(s.layouts[i].MaxSize)
+		}
+		return float64(s.counts[i])
+	}
+
+	order := make([]int, len(s.layouts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return fill(order[i]) < fill(order[j]) })
+	return order
+}
+
+// hasSameEmptyTeam checks if there is an empty team with the same layout before the team.
+func (s *arrangeSearch) hasSameEmptyTeam(team int) bool {
+	for i := range team {
+		if s.counts[i] == 0 && sameLayout(s.layouts[i], s.layouts[team]) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameLayout(a, b TeamLayout) bool {
+	return a.MinSize == b.MinSize && a.MaxSize == b.MaxSize && slices.Equal(a.Roles, b.Roles)
+}
+
+// cost returns the difference between the highest and the lowest average score of teams.
+func (s *arrangeSearch) cost() float64 {
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for i, cnt := range s.counts {
+		if cnt == 0 {
+			continue
+		}
+		avg := s.sums[i] / float64(cnt)
+		lowest, highest = min(lowest, avg), max(highest, avg)
+	}
+	if math.IsInf(lowest, 0) {
+		return 0.0
+	}
+	return highest - lowest
+}
+
+// teamScores returns the average score of each team.
+func teamScores(teams [][]*Player) []float64 {
+	scores := make([]float64, len(teams))
+	for i, team := range teams {
+		if len(team) == 0 {
+			continue
+		}
+		for _, pl := range team {
+			scores[i] += pl.skill()
+		}
+		scores[i] /= float64(len(team))
+	}
+	return scores
+}
+
+// performanceDeviation returns the performance deviation of a player used to predict win probability.
+// It is a sixth of the score range, as β of TrueSkill is of its initial rating.
+func (fp FilterParams) performanceDeviation() float64 {
+	return fp.ScoreRange / 6.0
+}
+
+// winProbability predicts the probability that each team wins, by the performance deviation beta of a player.
+// Probability of a team beating another is Φ((s1-s2)/(√2·β)) as in TrueSkill,
+// and the probability of a team is the sum of its pairwise probabilities divided by the number of pairs.
+func winProbability(scores []float64, beta float64) []float64 {
+	n := len(scores)
+	probs := make([]float64, n)
+	if n < 2 {
+		for i := range probs {
+			probs[i] = 1.0
+		}
+		return probs
+	}
+
+	pairs := float64(n*(n-1)) / 2.0
+	for i := range scores {
+		for j := range scores {
+			if i == j {
+				continue
+			}
+			probs[i] += normalCDF((scores[i]-scores[j])/(math.Sqrt2*beta)) / pairs
+		}
+	}
+	return probs
+}
+
+// normalCDF is the cumulative distribution function of the standard normal distribution.
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
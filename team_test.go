@@ -0,0 +1,157 @@
+package matchqueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_queue_arrangeTeams(t *testing.T) {
+	newParties := func(sizes ...int) []*party {
+		var parties []*party
+		id := PlayerID(0)
+		for _, size := range sizes {
+			p := &party{}
+			for range size {
+				id++
+				p.players = append(p.players, &Player{ID: id})
+			}
+			p.id = p.players[0].ID
+			parties = append(parties, p)
+		}
+		return parties
+	}
+
+	tests := []struct {
+		name       string
+		teams      []TeamLayout
+		candidates []*party
+		want       []int // number of players of each team
+	}{
+		{"balanced", nil, newParties(4, 1, 4, 1), []int{5, 5}},
+		{"unbalanced", nil, newParties(4, 1, 1), nil},
+		{"free for all", []TeamLayout{{MinSize: 1, MaxSize: 1}, {MinSize: 1, MaxSize: 1}, {MinSize: 1, MaxSize: 1}, {MinSize: 1, MaxSize: 1}}, newParties(1, 1, 1, 1), []int{1, 1, 1, 1}},
+		{"free for all short", []TeamLayout{{MinSize: 1, MaxSize: 1}, {MinSize: 1, MaxSize: 1}, {MinSize: 1, MaxSize: 1}, {MinSize: 1, MaxSize: 1}}, newParties(1, 1, 1), nil},
+		{"three squads", []TeamLayout{{MinSize: 3, MaxSize: 3}, {MinSize: 3, MaxSize: 3}, {MinSize: 3, MaxSize: 3}}, newParties(2, 3, 1, 2, 1), []int{3, 3, 3}},
+		{"asymmetric", []TeamLayout{{MinSize: 1, MaxSize: 1}, {MinSize: 4, MaxSize: 4}}, newParties(1, 2, 1, 1), []int{1, 4}},
+		{"too big party", []TeamLayout{{MinSize: 1, MaxSize: 1}, {MinSize: 4, MaxSize: 4}}, newParties(1, 5), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &queue{config: Config{Teams: tt.teams}}
+
+			got, _ := q.arrangeTeams(tt.candidates)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+
+			var counts []int
+			for _, team := range got {
+				cnt := 0
+				for _, p := range team {
+					cnt += len(p.players)
+				}
+				counts = append(counts, cnt)
+			}
+			assert.Equal(t, tt.want, counts)
+		})
+	}
+}
+
+func Test_queue_arrangeTeams_balance(t *testing.T) {
+	newParty := func(id PlayerID, scores ...float64) *party {
+		p := &party{id: id}
+		for i, score := range scores {
+			p.players = append(p.players, &Player{ID: id + PlayerID(i), Score: score})
+		}
+		return p
+	}
+
+	// strong players must be split into both teams
+	candidates := []*party{
+		newParty(1, 90.0), newParty(2, 55.0), newParty(3, 10.0), newParty(4, 45.0),
+		newParty(5, 50.0, 50.0),
+	}
+
+	q := &queue{}
+	got, _ := q.arrangeTeams(candidates)
+	assert.Len(t, got, 2)
+
+	var players [][]*Player
+	for _, team := range got {
+		var pl []*Player
+		for _, p := range team {
+			pl = append(pl, p.players...)
+		}
+		players = append(players, pl)
+	}
+
+	scores := teamScores(players)
+	assert.InDelta(t, scores[0], scores[1], 5.0)
+
+	// both players of the party 5 are in the same team
+	teamOf := map[PlayerID]int{}
+	for i, team := range players {
+		for _, pl := range team {
+			teamOf[pl.ID] = i
+		}
+	}
+	require.Contains(t, teamOf, PlayerID(5))
+	require.Contains(t, teamOf, PlayerID(6))
+	assert.Equal(t, teamOf[5], teamOf[6])
+}
+
+func Test_winProbability(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		beta   float64
+		want   []float64
+	}{
+		{"even", []float64{25.0, 25.0}, 25.0 / 6.0, []float64{0.5, 0.5}},
+		{"stronger", []float64{30.0, 25.0}, 25.0 / 6.0, []float64{0.8019280454239629, 1.0 - 0.8019280454239629}},
+		{"three", []float64{25.0, 25.0, 25.0}, 25.0 / 6.0, []float64{1.0 / 3.0, 1.0 / 3.0, 1.0 / 3.0}},
+		{"single", []float64{25.0}, 25.0 / 6.0, []float64{1.0}},
+		{"scaled", []float64{1800.0, 1500.0}, 1500.0 / 6.0, []float64{0.8019280454239629, 1.0 - 0.8019280454239629}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := winProbability(tt.scores, tt.beta)
+			assert.InDeltaSlice(t, tt.want, got, 1e-9)
+
+			sum := 0.0
+			for _, p := range got {
+				sum += p
+			}
+			assert.InDelta(t, 1.0, sum, 1e-9)
+		})
+	}
+}
+
+func Test_queue_winProbability(t *testing.T) {
+	// ratings of 1500 scale with the filter params of the scale
+	conf := DefaultConfig()
+	conf.FilterParams.ScoreMid, conf.FilterParams.ScoreRange = 1500.0, 1500.0
+	conf.MinNumToCreateGroup = 2
+	conf.MaxNumToCreateGroup = 2
+	conf.NumRoundToCreateGroup = 1
+	conf.InitMatchWindow = conf.MaxMatchWindow
+
+	q := New(conf)
+	require.NoError(t, q.AddPlayer([]*Player{{ID: 1, Rating: &Rating{Mean: 1520.0}}}))
+	require.NoError(t, q.AddPlayer([]*Player{{ID: 2, Rating: &Rating{Mean: 1500.0}}}))
+	groups, err := q.ProcMatching()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	g := groups[0]
+	require.Len(t, g.WinProbability, 2)
+	stronger := 0
+	if g.Players[1][0].ID == 1 {
+		stronger = 1
+	}
+	assert.InDelta(t, 0.5225555530725624, g.WinProbability[stronger], 1e-9)
+	assert.InDelta(t, 1.0-0.5225555530725624, g.WinProbability[1-stronger], 1e-9)
+}